@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/fakeupstream"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	fixturesDir := flag.String("fixtures", "", "directory with viacep.json and weatherapi.json, defaults to the embedded fixtures")
+
+	var cepFault, weatherFault fakeupstream.Fault
+	flag.DurationVar(&cepFault.Latency, "cep-latency", 0, "delay added to every ViaCEP response")
+	flag.Float64Var(&cepFault.ErrorRate, "cep-error-rate", 0, "fraction of ViaCEP requests that fail, from 0 to 1")
+	flag.IntVar(&cepFault.Status, "cep-error-status", http.StatusServiceUnavailable, "status of failed ViaCEP requests")
+	flag.DurationVar(&weatherFault.Latency, "weather-latency", 0, "delay added to every weatherapi.com response")
+	flag.Float64Var(&weatherFault.ErrorRate, "weather-error-rate", 0, "fraction of weatherapi.com requests that fail, from 0 to 1")
+	flag.IntVar(&weatherFault.Status, "weather-error-status", http.StatusInternalServerError, "status of failed weatherapi.com requests")
+	flag.Parse()
+
+	fixtures, loadErr := fakeupstream.LoadFixtures(*fixturesDir)
+	if loadErr != nil {
+		log.Fatalf("failed loading fixtures %s\n", loadErr.Error())
+	}
+
+	handler := fakeupstream.NewHandler(
+		fixtures,
+		fakeupstream.WithCEPFault(cepFault),
+		fakeupstream.WithWeatherFault(weatherFault),
+	)
+
+	log.Println("fake upstreams listening on", *addr)
+	if err := http.ListenAndServe(*addr, handler); err != nil {
+		log.Fatal(err)
+	}
+}
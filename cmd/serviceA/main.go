@@ -7,8 +7,10 @@ import (
 	"os/signal"
 
 	conf "github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
 	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/opentel"
-	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/web"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/serviceb"
+	"github.com/MatheusBenetti/opentelemetry/internal/services"
 	"go.opentelemetry.io/otel"
 )
 
@@ -16,33 +18,63 @@ func main() {
 	var cfg conf.Config
 	viperCfg := conf.NewViper("env.json")
 	viperCfg.ReadViper(&cfg)
+	defer viperCfg.Close()
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt)
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	providerShutdown, provErr := opentel.InitProvider(
+	provider, provErr := opentel.NewProvider(
 		"service_a_orchestration",
 		cfg.Zipkin.Endpoint,
+		opentel.WithConfig(cfg.Otel),
 	)
 	if provErr != nil {
 		return
 	}
+	otel.SetTextMapPropagator(opentel.Propagator())
 
 	defer func() {
-		if err := providerShutdown(ctx); err != nil {
+		if err := provider.Shutdown(ctx); err != nil {
 			log.Printf("failed shuting down the tracer provider %s\n", err.Error())
 		}
 	}()
 
-	server := web.Server{
-		TemplateData: web.TemplateData{
-			Title:           "Service A: Orchestration",
-			ExternalCallURL: cfg.ServiceB.Host,
-			RequestNameOtel: "service_a:all",
-			OTELTracer:      otel.Tracer("service_a"),
-		},
+	meterProvider, meterErr := opentel.NewMeterProvider(
+		"service_a_orchestration",
+		cfg.Otel.MetricsEndpoint,
+		opentel.WithConfig(cfg.Otel),
+	)
+	if meterErr != nil {
+		log.Printf("failed creating the meter provider %s\n", meterErr.Error())
+		return
+	}
+	defer func() {
+		if err := meterProvider.Shutdown(context.Background()); err != nil {
+			log.Printf("failed shuting down the meter provider %s\n", err.Error())
+		}
+	}()
+
+	var tempClient serviceb.Client = contract.NewClient(cfg.ServiceB.Host)
+	if cfg.ServiceA.Transport == serviceb.TransportGRPC {
+		grpcClient, dialErr := serviceb.NewGRPCClient(
+			cfg.ServiceB.GRPCHost,
+			serviceb.WithTracerProvider(provider),
+			serviceb.WithTimeout(cfg.ServiceB.GRPCTimeout),
+		)
+		if dialErr != nil {
+			log.Printf("failed connecting to service B over gRPC %s\n", dialErr.Error())
+			return
+		}
+		defer grpcClient.Close()
+		tempClient = grpcClient
+	}
+
+	server, serverErr := services.ServiceA(&cfg, tempClient, provider, meterProvider)
+	if serverErr != nil {
+		log.Println(serverErr)
+		return
 	}
 
 	server.Execute()
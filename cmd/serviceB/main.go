@@ -2,89 +2,70 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"log"
-	"net/http"
+	"net"
 	"os"
 	"os/signal"
 
 	"github.com/MatheusBenetti/opentelemetry/config"
 	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/opentel"
-	"github.com/MatheusBenetti/opentelemetry/internal/temperature/dto"
-	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
-	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/api"
-	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase"
+	"github.com/MatheusBenetti/opentelemetry/internal/services"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
 )
 
 func main() {
 	var cfg config.Config
 	viperCfg := config.NewViper("env.json")
 	viperCfg.ReadViper(&cfg)
+	defer viperCfg.Close()
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt)
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	providerShutdown, provErr := opentel.InitProvider(
+	provider, provErr := opentel.NewProvider(
 		"service_b",
 		cfg.Zipkin.Endpoint,
+		opentel.WithConfig(cfg.Otel),
 	)
 	if provErr != nil {
 		return
 	}
+	otel.SetTextMapPropagator(opentel.Propagator())
 
-	gw := usecase.NewGetWeather(
-		api.NewCEPFromAPI(&cfg),
-		api.NewWeatherFromAPI(&cfg),
-	)
+	gw, gwErr := services.GetWeather(&cfg, provider)
+	if gwErr != nil {
+		log.Println(gwErr)
+		return
+	}
 
 	defer func() {
-		if err := providerShutdown(ctx); err != nil {
+		if err := provider.Shutdown(ctx); err != nil {
 			log.Printf("failed shuting down the tracer provider %s\n", err.Error())
 		}
 	}()
 
-	mux := http.NewServeMux()
-	mux.HandleFunc(
-		"GET /temperature",
-		func(w http.ResponseWriter, r *http.Request) {
-			carrier := propagation.HeaderCarrier(r.Header)
-			hCtx := r.Context()
-			hCtx = otel.GetTextMapPropagator().Extract(hCtx, carrier)
-
-			tracer := otel.Tracer("service_b")
-			_, span := tracer.Start(hCtx, "service_b:all")
-			defer span.End()
-
-			temperature, execErr := gw.Execute(hCtx, dto.LocationInput{
-				CEP: r.URL.Query().Get("cep"),
-			})
-
-			switch {
-			case errors.Is(execErr, entity.ErrCEPNotValid):
-				http.Error(w, entity.ErrCEPNotValid.Error(), http.StatusUnprocessableEntity)
-				return
-			case errors.Is(execErr, entity.ErrCEPNotFound):
-				http.Error(w, entity.ErrCEPNotFound.Error(), http.StatusNotFound)
-				return
-			case execErr != nil:
-				http.Error(w, execErr.Error(), http.StatusInternalServerError)
-				return
-			}
+	if cfg.ServiceB.GRPCPort != "" {
+		listener, listenErr := net.Listen("tcp", ":"+cfg.ServiceB.GRPCPort)
+		if listenErr != nil {
+			log.Printf("failed listening for gRPC %s\n", listenErr.Error())
+			return
+		}
 
-			w.Header().Set("Content-Type", "application/json")
-			if err := json.NewEncoder(w).Encode(temperature); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+		grpcServer := services.ServiceBGRPC(gw, provider)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Printf("gRPC server error %s\n", err.Error())
 			}
-			w.WriteHeader(http.StatusOK)
-		},
-	)
+		}()
+	}
 
-	if err := http.ListenAndServe(":"+cfg.ServiceB.Port, mux); err != nil {
+	server, serverErr := services.ServiceB(&cfg, gw, provider)
+	if serverErr != nil {
+		log.Println(serverErr)
 		return
 	}
+
+	server.Execute()
 }
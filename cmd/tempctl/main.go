@@ -0,0 +1,152 @@
+// Command tempctl looks up the temperature of one or many CEPs through
+// service A, service B or the use case itself, and can print the trace of
+// each lookup without opening Zipkin.
+//
+//	tempctl -target a -units C,F 95670-084 01001000
+//	tempctl -target local -trace -output json < ceps.txt
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/opentel"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const lookupSpan = "tempctl:lookup"
+
+func main() {
+	var opts lookupOptions
+	flag.StringVar(&opts.target, "target", targetServiceA, "where to look up: a (service A), b (service B) or local (the use case with the configured providers)")
+	flag.StringVar(&opts.url, "url", "", "base URL of the target service, defaults to http://localhost:8080 for a and service_b.host for b")
+	flag.StringVar(&opts.config, "config", "env.json", "config file, read for the local target and service B's default URL")
+	flag.StringVar(&opts.apiKey, "api-key", "", "key sent to service A")
+	flag.StringVar(&opts.units, "units", "", "comma separated units, e.g. C,F,K,R,Re")
+	flag.StringVar(&opts.language, "lang", "", "language of the weather condition, e.g. pt-BR")
+	output := flag.String("output", "table", "output format: table or json")
+	showTrace := flag.Bool("trace", false, "print each lookup's trace ID and a waterfall of the spans recorded in this process")
+	concurrency := flag.Int("concurrency", 4, "lookups run at once")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: tempctl [flags] cep... (CEPs are read from stdin when none are given)\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *output != "table" && *output != "json" {
+		log.Fatalf("unknown output %q\n", *output)
+	}
+
+	ceps := flag.Args()
+	if len(ceps) == 0 {
+		var readErr error
+		if ceps, readErr = readCEPs(os.Stdin); readErr != nil {
+			log.Fatalf("failed reading CEPs %s\n", readErr.Error())
+		}
+	}
+	if len(ceps) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+	otel.SetTextMapPropagator(opentel.Propagator())
+	opts.tracerProvider = provider
+
+	lookup, lookupErr := newLookup(opts)
+	if lookupErr != nil {
+		log.Fatalf("failed preparing the %s target %s\n", opts.target, lookupErr.Error())
+	}
+
+	results := run(ctx, provider.Tracer("tempctl"), lookup, opts.target, ceps, *concurrency)
+	if *showTrace {
+		attachSpans(results, exporter.GetSpans())
+	}
+
+	var writeErr error
+	if *output == "json" {
+		writeErr = writeJSON(os.Stdout, results)
+	} else {
+		writeErr = writeTable(os.Stdout, results, *showTrace)
+	}
+	if writeErr != nil {
+		log.Fatalf("failed writing the results %s\n", writeErr.Error())
+	}
+
+	for _, result := range results {
+		if result.Error != "" {
+			os.Exit(1)
+		}
+	}
+}
+
+// readCEPs returns the whitespace separated CEPs in r.
+func readCEPs(r io.Reader) ([]string, error) {
+	var ceps []string
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		ceps = append(ceps, scanner.Text())
+	}
+
+	return ceps, scanner.Err()
+}
+
+// run looks up every CEP under its own root span, so each one gets its own
+// trace, and returns the results in the order of ceps.
+func run(ctx context.Context, tracer trace.Tracer, lookup lookupFunc, target string, ceps []string, concurrency int) []result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]result, len(ceps))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, cep := range ceps {
+		wg.Add(1)
+		go func(i int, cep string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			lCtx, span := tracer.Start(ctx, lookupSpan, trace.WithSpanKind(trace.SpanKindClient))
+			defer span.End()
+			span.SetAttributes(
+				attribute.String("tempctl.target", target),
+				attribute.String("tempctl.cep", cep),
+			)
+
+			results[i] = result{CEP: cep, TraceID: span.SpanContext().TraceID().String()}
+			out, err := lookup(lCtx, cep)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				results[i].Error = strings.TrimSpace(err.Error())
+				return
+			}
+			results[i].Temperature = &out
+		}(i, cep)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const waterfallWidth = 40
+
+type result struct {
+	CEP         string                `json:"cep"`
+	TraceID     string                `json:"trace_id"`
+	Temperature *contract.CEPResponse `json:"temperature,omitempty"`
+	Error       string                `json:"error,omitempty"`
+	Spans       []spanView            `json:"spans,omitempty"`
+}
+
+// spanView is a span recorded in this process, listed parents first.
+type spanView struct {
+	Name       string    `json:"name"`
+	SpanID     string    `json:"span_id"`
+	ParentID   string    `json:"parent_id,omitempty"`
+	Start      time.Time `json:"start"`
+	DurationMS float64   `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+	Depth      int       `json:"depth"`
+
+	duration time.Duration
+}
+
+// attachSpans gives each result the spans of its trace, each parent followed
+// by its children in the order they started.
+func attachSpans(results []result, spans tracetest.SpanStubs) {
+	byTrace := map[string]tracetest.SpanStubs{}
+	for _, span := range spans {
+		traceID := span.SpanContext.TraceID().String()
+		byTrace[traceID] = append(byTrace[traceID], span)
+	}
+
+	for i := range results {
+		results[i].Spans = tree(byTrace[results[i].TraceID])
+	}
+}
+
+func tree(spans tracetest.SpanStubs) []spanView {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].StartTime.Before(spans[j].StartTime) })
+
+	present := map[string]bool{}
+	for _, span := range spans {
+		present[span.SpanContext.SpanID().String()] = true
+	}
+
+	children := map[string]tracetest.SpanStubs{}
+	var roots tracetest.SpanStubs
+	for _, span := range spans {
+		parentID := span.Parent.SpanID().String()
+		if !span.Parent.IsValid() || !present[parentID] {
+			roots = append(roots, span)
+			continue
+		}
+		children[parentID] = append(children[parentID], span)
+	}
+
+	var views []spanView
+	var walk func(spans tracetest.SpanStubs, depth int)
+	walk = func(spans tracetest.SpanStubs, depth int) {
+		for _, span := range spans {
+			view := spanView{
+				Name:     span.Name,
+				SpanID:   span.SpanContext.SpanID().String(),
+				Start:    span.StartTime,
+				Depth:    depth,
+				duration: span.EndTime.Sub(span.StartTime),
+			}
+			if span.Parent.IsValid() {
+				view.ParentID = span.Parent.SpanID().String()
+			}
+			if span.Status.Code == codes.Error {
+				view.Error = span.Status.Description
+			}
+			view.DurationMS = float64(view.duration.Microseconds()) / 1000
+			views = append(views, view)
+
+			walk(children[view.SpanID], depth+1)
+		}
+	}
+	walk(roots, 0)
+
+	return views
+}
+
+func writeJSON(w io.Writer, results []result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(results)
+}
+
+// writeTable prints one row per CEP with a column for each unit any of them
+// returned, followed by the waterfalls when showTrace is set.
+func writeTable(w io.Writer, results []result, showTrace bool) error {
+	units := []struct {
+		name  string
+		value func(contract.CEPResponse) *float64
+	}{
+		{"C", func(r contract.CEPResponse) *float64 { return r.TempC }},
+		{"F", func(r contract.CEPResponse) *float64 { return r.TempF }},
+		{"K", func(r contract.CEPResponse) *float64 { return r.TempK }},
+		{"R", func(r contract.CEPResponse) *float64 { return r.TempR }},
+		{"Re", func(r contract.CEPResponse) *float64 { return r.TempRe }},
+	}
+	shown := units[:0:0]
+	for _, unit := range units {
+		for _, result := range results {
+			if result.Temperature != nil && unit.value(*result.Temperature) != nil {
+				shown = append(shown, unit)
+				break
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"CEP", "CITY", "CONDITION"}
+	for _, unit := range shown {
+		header = append(header, unit.name)
+	}
+	header = append(header, "ERROR")
+	if showTrace {
+		header = append(header, "TRACE")
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, result := range results {
+		row := []string{result.CEP, "-", "-"}
+		if result.Temperature != nil {
+			row = []string{result.CEP, result.Temperature.City, orDash(result.Temperature.Condition)}
+		}
+		for _, unit := range shown {
+			value := "-"
+			if result.Temperature != nil {
+				if v := unit.value(*result.Temperature); v != nil {
+					value = strconv.FormatFloat(*v, 'f', -1, 64)
+				}
+			}
+			row = append(row, value)
+		}
+		row = append(row, orDash(result.Error))
+		if showTrace {
+			row = append(row, result.TraceID)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if !showTrace {
+		return nil
+	}
+	for _, result := range results {
+		fmt.Fprintf(w, "\ntrace %s (%s)\n", result.TraceID, result.CEP)
+		if err := writeWaterfall(w, result.Spans); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeWaterfall draws each span as a bar placed by its start and length
+// relative to the whole trace.
+func writeWaterfall(w io.Writer, spans []spanView) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	start, end := spans[0].Start, spans[0].Start.Add(spans[0].duration)
+	for _, span := range spans {
+		if span.Start.Before(start) {
+			start = span.Start
+		}
+		if spanEnd := span.Start.Add(span.duration); spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+	total := end.Sub(start)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, span := range spans {
+		offset, width := 0, waterfallWidth
+		if total > 0 {
+			offset = min(int(float64(span.Start.Sub(start))/float64(total)*waterfallWidth), waterfallWidth-1)
+			width = int(float64(span.duration) / float64(total) * waterfallWidth)
+		}
+		width = max(1, min(width, waterfallWidth-offset))
+
+		bar := strings.Repeat(" ", offset) + strings.Repeat("█", width) + strings.Repeat(" ", waterfallWidth-offset-width)
+		name := strings.Repeat("  ", span.Depth) + span.Name
+		if span.Error != "" {
+			name += " (error: " + span.Error + ")"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t|%s|\n", name, span.duration.Round(time.Microsecond), bar)
+	}
+
+	return tw.Flush()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRunRecordsOneTracePerCEP(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test")
+
+	lookup := func(ctx context.Context, cep string) (contract.CEPResponse, error) {
+		_, cepSpan := tracer.Start(ctx, "service_b:get_CEP")
+		cepSpan.End()
+		if cep == "99999999" {
+			return contract.CEPResponse{}, errors.New("can not found zipcode")
+		}
+
+		_, weatherSpan := tracer.Start(ctx, "service_b:get_weather")
+		weatherSpan.End()
+		celsius := 14.0
+		return contract.CEPResponse{City: "Gramado", TempC: &celsius}, nil
+	}
+
+	results := run(context.Background(), tracer, lookup, targetLocal, []string{"95670084", "99999999"}, 2)
+	attachSpans(results, exporter.GetSpans())
+
+	require.Len(t, results, 2)
+	assert.NotEqual(t, results[0].TraceID, results[1].TraceID)
+
+	names := func(spans []spanView) []string {
+		var out []string
+		for _, span := range spans {
+			out = append(out, strings.Repeat(">", span.Depth)+span.Name)
+		}
+		return out
+	}
+	assert.Equal(t, []string{lookupSpan, ">service_b:get_CEP", ">service_b:get_weather"}, names(results[0].Spans))
+	assert.Equal(t, []string{lookupSpan, ">service_b:get_CEP"}, names(results[1].Spans))
+	assert.Equal(t, "can not found zipcode", results[1].Error)
+	assert.Equal(t, "can not found zipcode", results[1].Spans[0].Error)
+	assert.Equal(t, results[1].Spans[0].SpanID, results[1].Spans[1].ParentID)
+}
+
+func TestWriteTable(t *testing.T) {
+	celsius, reaumur := 14.0, 11.2
+	results := []result{
+		{CEP: "95670084", TraceID: "a", Temperature: &contract.CEPResponse{City: "Gramado", Condition: "Sunny", TempC: &celsius, TempRe: &reaumur}},
+		{CEP: "123", TraceID: "b", Error: "invalid zipcode"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, writeTable(&out, results, false))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"CEP", "CITY", "CONDITION", "C", "Re", "ERROR"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"95670084", "Gramado", "Sunny", "14", "11.2", "-"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"123", "-", "-", "-", "-", "invalid", "zipcode"}, strings.Fields(lines[2]))
+}
+
+func TestWriteWaterfallPlacesSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	root.End()
+
+	spans := tree(exporter.GetSpans())
+	var out bytes.Buffer
+	require.NoError(t, writeWaterfall(&out, spans))
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "|"+strings.Repeat("█", waterfallWidth)+"|")
+	assert.Contains(t, lines[1], "    child")
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/services"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/dto"
+	"github.com/MatheusBenetti/opentelemetry/pkg/client"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	targetServiceA = "a"
+	targetServiceB = "b"
+	targetLocal    = "local"
+
+	defaultServiceAURL = "http://localhost:8080"
+)
+
+type lookupOptions struct {
+	target   string
+	url      string
+	config   string
+	apiKey   string
+	units    string
+	language string
+	// tracerProvider records the spans of the local target's repositories.
+	tracerProvider trace.TracerProvider
+}
+
+// lookupFunc returns the temperature for a CEP in service A's format, whatever
+// the target.
+type lookupFunc func(ctx context.Context, cep string) (contract.CEPResponse, error)
+
+func newLookup(opts lookupOptions) (lookupFunc, error) {
+	switch opts.target {
+	case targetServiceA:
+		return serviceALookup(opts), nil
+	case targetServiceB:
+		return serviceBLookup(opts)
+	case targetLocal:
+		return localLookup(opts)
+	default:
+		return nil, fmt.Errorf("unknown target %q, want %s, %s or %s", opts.target, targetServiceA, targetServiceB, targetLocal)
+	}
+}
+
+func serviceALookup(opts lookupOptions) lookupFunc {
+	baseURL := opts.url
+	if baseURL == "" {
+		baseURL = defaultServiceAURL
+	}
+
+	clientOpts := []client.Option{client.WithRetries(0, 0)}
+	if opts.apiKey != "" {
+		clientOpts = append(clientOpts, client.WithAPIKey(opts.apiKey))
+	}
+	c := client.New(baseURL, clientOpts...)
+
+	var lookupOpts []client.LookupOption
+	if opts.units != "" {
+		lookupOpts = append(lookupOpts, client.Units(strings.Split(opts.units, ",")...))
+	}
+	if opts.language != "" {
+		lookupOpts = append(lookupOpts, client.Language(opts.language))
+	}
+
+	return func(ctx context.Context, cep string) (contract.CEPResponse, error) {
+		out, err := c.Temperature(ctx, cep, lookupOpts...)
+		return contract.CEPResponse(out), err
+	}
+}
+
+func serviceBLookup(opts lookupOptions) (lookupFunc, error) {
+	host := opts.url
+	if host == "" {
+		cfg, cfgErr := config.ReadFile(opts.config)
+		if cfgErr != nil {
+			return nil, cfgErr
+		}
+		host = cfg.ServiceB.Host
+	}
+	c := contract.NewClient(host)
+
+	return func(ctx context.Context, cep string) (contract.CEPResponse, error) {
+		out, err := c.Temperature(ctx, contract.TemperatureRequest{CEP: cep, Units: opts.units, Language: opts.language})
+		if err != nil {
+			return contract.CEPResponse{}, err
+		}
+
+		return out.CEPResponse(), nil
+	}, nil
+}
+
+// localLookup runs the use case in this process with the providers from the
+// config, as service B would, so its repository spans land in the waterfall.
+func localLookup(opts lookupOptions) (lookupFunc, error) {
+	cfg, cfgErr := config.ReadFile(opts.config)
+	if cfgErr != nil {
+		return nil, cfgErr
+	}
+
+	gw, gwErr := services.GetWeather(cfg, opts.tracerProvider)
+	if gwErr != nil {
+		return nil, gwErr
+	}
+
+	return func(ctx context.Context, cep string) (contract.CEPResponse, error) {
+		out, err := gw.Execute(ctx, dto.LocationInput{CEP: cep, Units: opts.units, Language: opts.language})
+		if err != nil {
+			return contract.CEPResponse{}, err
+		}
+
+		return out.CEPResponse(), nil
+	}, nil
+}
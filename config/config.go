@@ -1,5 +1,10 @@
 package config
 
+import (
+	"sync/atomic"
+	"time"
+)
+
 type Config struct {
 	ServiceA    ServiceA
 	ServiceB    ServiceB
@@ -10,21 +15,80 @@ type Config struct {
 }
 
 type Temperature struct {
-	ApiKey string
-	URL    string
+	ApiKey     Secret
+	ApiKeyFile string
+	URL        string
+	Precision  map[string]int
+	// reloadedKey replaces ApiKey when Viper watches the config.
+	reloadedKey *atomic.Pointer[Secret]
+}
+
+// CurrentAPIKey returns the weatherapi.com key, as last reloaded when the
+// config is watched by Viper. It is safe to call while the key rotates.
+func (t *Temperature) CurrentAPIKey() Secret {
+	if t.reloadedKey != nil {
+		if key := t.reloadedKey.Load(); key != nil {
+			return *key
+		}
+	}
+
+	return t.ApiKey
 }
 
 type CEP struct {
-	URL string
+	URL        string
+	RangesFile string
 }
 
 type ServiceB struct {
-	Port string
-	Host string
+	Port     string
+	Host     string
+	GRPCPort string
+	GRPCHost string
+	// GRPCTimeout bounds each gRPC lookup made by service A, and the wait for
+	// each result of a batch.
+	GRPCTimeout time.Duration
+	HTTP        HTTPServer
 }
 
 type ServiceA struct {
-	Port string
+	Port         string
+	Transport    string
+	MaxBodyBytes int64
+	HTTP         HTTPServer
+	RateLimit    RateLimit
+	Auth         Auth
+}
+
+// HTTPServer tunes a service's http.Server. Zero values fall back to the
+// defaults of the httpserver package.
+type HTTPServer struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+}
+
+type Auth struct {
+	Enabled bool
+	Header  string
+	Keys    []APIKey
+}
+
+// APIKey identifies a client of service A. Hash is the hex encoded SHA-256 of
+// the key, so the plain key never has to live in the config file.
+type APIKey struct {
+	ID   string `mapstructure:"id"`
+	Hash string `mapstructure:"hash"`
+}
+
+type RateLimit struct {
+	Enabled     bool
+	RPS         float64
+	Burst       int
+	ClientRPS   float64
+	ClientBurst int
 }
 
 type Zipkin struct {
@@ -32,6 +96,12 @@ type Zipkin struct {
 	Endpoint string
 }
 
+// Otel describes the deployment in the resource of every span. Attributes
+// are overridden by OTEL_RESOURCE_ATTRIBUTES. MetricsEndpoint is the OTLP/HTTP
+// URL the metrics are pushed to; without it they aren't exported.
 type Otel struct {
-	Host string
+	Host            string
+	MetricsEndpoint string
+	Environment     string
+	Attributes      map[string]string
 }
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// Secret holds a sensitive configuration value. Its String, GoString and
+// JSON forms are redacted so it can't leak through logs or dumps; use Reveal
+// to get the raw value when it has to be sent upstream.
+type Secret string
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return redactedValue
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// Redact replaces every occurrence of the secret, raw or query-escaped, in text.
+func (s Secret) Redact(text string) string {
+	if s == "" {
+		return text
+	}
+
+	text = strings.ReplaceAll(text, string(s), redactedValue)
+	if escaped := url.QueryEscape(string(s)); escaped != string(s) {
+		text = strings.ReplaceAll(text, escaped, redactedValue)
+	}
+
+	return text
+}
+
+// RedactError returns err with the secret removed from its message and from
+// the message of every error it wraps. errors.Is still matches the wrapped
+// errors, but errors.As and errors.Unwrap only reach their redacted copies,
+// so a wrapped *url.Error can't hand the raw URL back.
+func (s Secret) RedactError(err error) error {
+	if err == nil || s == "" {
+		return err
+	}
+
+	return &redactedError{err: err, msg: s.Redact(err.Error()), secret: s}
+}
+
+type redactedError struct {
+	err    error
+	msg    string
+	secret Secret
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.secret.RedactError(errors.Unwrap(e.err))
+}
+
+func (e *redactedError) Is(target error) bool {
+	return errors.Is(e.err, target)
+}
+
+// loadSecret resolves a secret in order of precedence: the envKey environment
+// variable, the content of file (Docker secrets and Kubernetes mounted files),
+// then the plain config value.
+func loadSecret(envKey, value, file string) (Secret, error) {
+	if env, ok := os.LookupEnv(envKey); ok && env != "" {
+		return Secret(strings.TrimSpace(env)), nil
+	}
+
+	if file != "" {
+		content, readErr := os.ReadFile(file)
+		if readErr != nil {
+			return "", readErr
+		}
+
+		return Secret(strings.TrimSpace(string(content))), nil
+	}
+
+	return Secret(value), nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretRedaction(t *testing.T) {
+	secret := Secret("my+key")
+
+	assert.Equal(t, "[REDACTED]", secret.String())
+	assert.Equal(t, "[REDACTED] [REDACTED]", fmt.Sprintf("%v %#v", secret, secret))
+	assert.Equal(t, "my+key", secret.Reveal())
+	assert.Equal(t, "?key=[REDACTED]&q=x", secret.Redact("?key="+url.QueryEscape("my+key")+"&q=x"))
+
+	baseErr := errors.New("request to ?key=my+key failed")
+	redacted := secret.RedactError(baseErr)
+	assert.Equal(t, "request to ?key=[REDACTED] failed", redacted.Error())
+	assert.ErrorIs(t, redacted, baseErr)
+}
+
+func TestRedactErrorChain(t *testing.T) {
+	secret := Secret("my+key")
+	urlErr := &url.Error{Op: "Get", URL: "https://api.weatherapi.com/v1/current.json?key=my%2Bkey", Err: context.DeadlineExceeded}
+	redacted := secret.RedactError(fmt.Errorf("weather: %w", urlErr))
+
+	assert.ErrorIs(t, redacted, context.DeadlineExceeded)
+	assert.ErrorIs(t, redacted, urlErr)
+
+	var raw *url.Error
+	assert.False(t, errors.As(redacted, &raw), "the raw *url.Error must not be reachable")
+
+	for err := redacted; err != nil; err = errors.Unwrap(err) {
+		assert.NotContains(t, err.Error(), "my%2Bkey")
+		assert.NotContains(t, err.Error(), "my+key")
+	}
+}
+
+func TestLoadSecret(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "api_key")
+	assert.NoError(t, os.WriteFile(file, []byte("from-file\n"), 0o600))
+
+	secret, err := loadSecret("TEST_SECRET_ENV", "from-config", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "from-config", secret.Reveal())
+
+	secret, err = loadSecret("TEST_SECRET_ENV", "from-config", file)
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", secret.Reveal())
+
+	t.Setenv("TEST_SECRET_ENV", "from-env")
+	secret, err = loadSecret("TEST_SECRET_ENV", "from-config", file)
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", secret.Reveal())
+}
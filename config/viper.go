@@ -3,15 +3,35 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
-const fileExtension = "json"
+const (
+	fileExtension = "json"
+
+	weatherAPIKeyEnv     = "WEATHER_API_KEY"
+	weatherAPIKeyFileEnv = "WEATHER_API_KEY_FILE"
+
+	// cepURLEnv and weatherAPIURLEnv point service B at other upstreams, such
+	// as cmd/fakeupstreams, without editing env.json.
+	cepURLEnv        = "CEP_URL"
+	weatherAPIURLEnv = "WEATHER_API_URL"
+)
 
 type Viper struct {
 	fileName string
+
+	// mu serialises the reloads triggered by the config file and by the
+	// secret file, which run on their own goroutines.
+	mu            sync.Mutex
+	watchedSecret string
+	secretWatcher *fsnotify.Watcher
+	closed        bool
 }
 
 func NewViper(fileName string) *Viper {
@@ -31,24 +51,202 @@ func (v *Viper) ReadViper(config *Config) {
 		os.Exit(1)
 	}
 
+	v.mu.Lock()
 	v.readConfig(config)
+	v.mu.Unlock()
+
 	viper.WatchConfig()
 	viper.OnConfigChange(func(e fsnotify.Event) {
 		fmt.Println("Config file changed:", e.Name)
-		v.readConfig(config)
+		v.reloadSecret(config)
 	})
 }
 
+// ReadFile reads the config at path, relative to the working directory or
+// absolute, once. Unlike ReadViper it neither watches the config nor the
+// secret file and returns its errors, for short-lived commands.
+func ReadFile(path string) (*Config, error) {
+	viper.SetConfigFile(path)
+	viper.SetConfigType(fileExtension)
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading config file %s: %w", path, err)
+	}
+
+	// A closed Viper never starts the secret watcher.
+	v := &Viper{fileName: path, closed: true}
+	var c Config
+	v.readConfig(&c)
+
+	return &c, nil
+}
+
+// Close stops watching the secret file. Later config changes still reload
+// the key, but no longer start a watcher.
+func (v *Viper) Close() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.closed = true
+	v.watchedSecret = ""
+	if v.secretWatcher == nil {
+		return nil
+	}
+	err := v.secretWatcher.Close()
+	v.secretWatcher = nil
+
+	return err
+}
+
+// readConfig fills c once, before it is shared. Later changes only reach the
+// weatherapi.com key, through reloadSecret, as the rest of the config is
+// copied by the services when they start.
 func (v *Viper) readConfig(c *Config) {
 	c.CEP.URL = viper.GetString("cep.url")
+	if url, ok := os.LookupEnv(cepURLEnv); ok && url != "" {
+		c.CEP.URL = url
+	}
+	c.CEP.RangesFile = viper.GetString("cep.ranges_file")
 	c.Temperature.URL = viper.GetString("temperature.url")
-	c.Temperature.ApiKey = viper.GetString("temperature.api_key")
+	if url, ok := os.LookupEnv(weatherAPIURLEnv); ok && url != "" {
+		c.Temperature.URL = url
+	}
+	c.Temperature.Precision = nil
+	if err := viper.UnmarshalKey("temperature.precision", &c.Temperature.Precision); err != nil {
+		fmt.Println("error reading temperature precision:", err)
+	}
+	c.Temperature.ApiKeyFile = secretFile()
+	c.Temperature.reloadedKey = new(atomic.Pointer[Secret])
+	v.refreshSecret(c)
+	if key := c.Temperature.reloadedKey.Load(); key != nil {
+		c.Temperature.ApiKey = *key
+	}
 
 	c.ServiceA.Port = viper.GetString("service_a.port")
+	c.ServiceA.Transport = viper.GetString("service_a.transport")
+	c.ServiceA.MaxBodyBytes = viper.GetInt64("service_a.max_body_bytes")
+	c.ServiceA.HTTP = readHTTPServer("service_a.http")
+	c.ServiceA.RateLimit.Enabled = viper.GetBool("service_a.rate_limit.enabled")
+	c.ServiceA.RateLimit.RPS = viper.GetFloat64("service_a.rate_limit.rps")
+	c.ServiceA.RateLimit.Burst = viper.GetInt("service_a.rate_limit.burst")
+	c.ServiceA.RateLimit.ClientRPS = viper.GetFloat64("service_a.rate_limit.client_rps")
+	c.ServiceA.RateLimit.ClientBurst = viper.GetInt("service_a.rate_limit.client_burst")
+	c.ServiceA.Auth.Enabled = viper.GetBool("service_a.auth.enabled")
+	c.ServiceA.Auth.Header = viper.GetString("service_a.auth.header")
+	c.ServiceA.Auth.Keys = nil
+	if err := viper.UnmarshalKey("service_a.auth.keys", &c.ServiceA.Auth.Keys); err != nil {
+		fmt.Println("error reading API keys:", err)
+	}
 
 	c.ServiceB.Port = viper.GetString("service_b.port")
 	c.ServiceB.Host = viper.GetString("service_b.host")
+	c.ServiceB.GRPCPort = viper.GetString("service_b.grpc_port")
+	c.ServiceB.GRPCHost = viper.GetString("service_b.grpc_host")
+	c.ServiceB.GRPCTimeout = viper.GetDuration("service_b.grpc_timeout")
+	c.ServiceB.HTTP = readHTTPServer("service_b.http")
 
 	c.Zipkin.Host = viper.GetString("zipkin.host")
 	c.Zipkin.Endpoint = viper.GetString("zipkin.endpoint")
+
+	c.Otel.Host = viper.GetString("otel.host")
+	c.Otel.MetricsEndpoint = viper.GetString("otel.metrics_endpoint")
+	c.Otel.Environment = viper.GetString("otel.environment")
+	c.Otel.Attributes = viper.GetStringMapString("otel.attributes")
+}
+
+func secretFile() string {
+	if file, ok := os.LookupEnv(weatherAPIKeyFileEnv); ok && file != "" {
+		return file
+	}
+
+	return viper.GetString("temperature.api_key_file")
+}
+
+func readHTTPServer(key string) HTTPServer {
+	return HTTPServer{
+		ReadHeaderTimeout: viper.GetDuration(key + ".read_header_timeout"),
+		ReadTimeout:       viper.GetDuration(key + ".read_timeout"),
+		WriteTimeout:      viper.GetDuration(key + ".write_timeout"),
+		IdleTimeout:       viper.GetDuration(key + ".idle_timeout"),
+		MaxHeaderBytes:    viper.GetInt(key + ".max_header_bytes"),
+	}
+}
+
+// reloadSecret refreshes the weatherapi.com key after the config or the
+// secret file changed.
+func (v *Viper) reloadSecret(c *Config) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.refreshSecret(c)
+}
+
+// refreshSecret loads the weatherapi.com key into c.Temperature, to be read
+// with CurrentAPIKey, and watches the file it came from. v.mu must be held.
+func (v *Viper) refreshSecret(c *Config) {
+	file := secretFile()
+	apiKey, keyErr := loadSecret(
+		weatherAPIKeyEnv,
+		viper.GetString("temperature.api_key"),
+		file,
+	)
+	if keyErr != nil {
+		fmt.Println("error reading weather API key:", keyErr)
+	} else {
+		c.Temperature.reloadedKey.Store(&apiKey)
+	}
+
+	v.watchSecret(c, file)
+}
+
+// watchSecret reloads the key whenever the secret file changes, so keys can
+// be rotated without a restart. The parent directory is watched because
+// Kubernetes updates mounted secrets by swapping a symlink. A previous
+// watcher is closed when the file moves. v.mu must be held.
+func (v *Viper) watchSecret(c *Config, file string) {
+	if v.closed || file == v.watchedSecret {
+		return
+	}
+	if v.secretWatcher != nil {
+		v.secretWatcher.Close()
+		v.secretWatcher = nil
+	}
+	v.watchedSecret = ""
+	if file == "" {
+		return
+	}
+
+	watcher, watchErr := fsnotify.NewWatcher()
+	if watchErr != nil {
+		fmt.Println("error watching secret file:", watchErr)
+		return
+	}
+
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		fmt.Println("error watching secret file:", err)
+		watcher.Close()
+		return
+	}
+	v.watchedSecret = file
+	v.secretWatcher = watcher
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+					continue
+				}
+				fmt.Println("Secret file changed:", event.Name)
+				v.reloadSecret(c)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fmt.Println("error watching secret file:", err)
+			}
+		}
+	}()
 }
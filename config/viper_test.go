@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadSecret(t *testing.T) {
+	first := filepath.Join(t.TempDir(), "api_key")
+	require.NoError(t, os.WriteFile(first, []byte("first-key\n"), 0o600))
+	t.Setenv(weatherAPIKeyFileEnv, first)
+
+	v := NewViper("env.json")
+	c := &Config{Temperature: Temperature{reloadedKey: new(atomic.Pointer[Secret])}}
+	v.reloadSecret(c)
+	t.Cleanup(func() { assert.NoError(t, v.Close()) })
+	assert.Equal(t, "first-key", c.Temperature.CurrentAPIKey().Reveal())
+
+	require.NoError(t, os.WriteFile(first, []byte("rotated-key\n"), 0o600))
+	assert.Eventually(t, func() bool {
+		return c.Temperature.CurrentAPIKey().Reveal() == "rotated-key"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	firstWatcher := v.secretWatcher
+	second := filepath.Join(t.TempDir(), "api_key")
+	require.NoError(t, os.WriteFile(second, []byte("second-key\n"), 0o600))
+	t.Setenv(weatherAPIKeyFileEnv, second)
+	v.reloadSecret(c)
+	assert.Equal(t, "second-key", c.Temperature.CurrentAPIKey().Reveal())
+	assert.NotSame(t, firstWatcher, v.secretWatcher)
+	assert.Equal(t, second, v.watchedSecret)
+
+	// The first file is no longer watched.
+	require.NoError(t, os.WriteFile(first, []byte("stale-key\n"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "second-key", c.Temperature.CurrentAPIKey().Reveal())
+}
+
+func TestCloseStopsWatchingSecret(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "api_key")
+	require.NoError(t, os.WriteFile(file, []byte("first-key\n"), 0o600))
+	t.Setenv(weatherAPIKeyFileEnv, file)
+
+	v := NewViper("env.json")
+	c := &Config{Temperature: Temperature{reloadedKey: new(atomic.Pointer[Secret])}}
+	v.reloadSecret(c)
+	require.NotNil(t, v.secretWatcher)
+
+	require.NoError(t, v.Close())
+	assert.Nil(t, v.secretWatcher)
+
+	require.NoError(t, os.WriteFile(file, []byte("rotated-key\n"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "first-key", c.Temperature.CurrentAPIKey().Reveal())
+
+	// A config change still reloads the key, without watching the file again.
+	v.reloadSecret(c)
+	assert.Equal(t, "rotated-key", c.Temperature.CurrentAPIKey().Reveal())
+	assert.Nil(t, v.secretWatcher)
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api_key")
+	require.NoError(t, os.WriteFile(file, []byte("file-key\n"), 0o600))
+	t.Setenv(weatherAPIKeyEnv, "")
+	t.Setenv(weatherAPIKeyFileEnv, file)
+
+	path := filepath.Join(dir, "tempctl.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"service_b": {"host": "http://service-b:8081"}}`), 0o600))
+
+	c, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "http://service-b:8081", c.ServiceB.Host)
+	assert.Equal(t, "file-key", c.Temperature.CurrentAPIKey().Reveal())
+
+	// The secret file is read once, not watched.
+	require.NoError(t, os.WriteFile(file, []byte("rotated-key\n"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "file-key", c.Temperature.CurrentAPIKey().Reveal())
+
+	_, err = ReadFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestCurrentAPIKeyWithoutReload(t *testing.T) {
+	temperature := Temperature{ApiKey: "static-key"}
+	assert.Equal(t, "static-key", temperature.CurrentAPIKey().Reveal())
+}
@@ -0,0 +1,282 @@
+// Package cassette records upstream HTTP interactions to a file and replays
+// them, so repositories can be tested against real ViaCEP and weatherapi.com
+// payloads without calling them.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ModeEnv selects the mode of cassettes that don't set one explicitly.
+const ModeEnv = "CASSETTE_MODE"
+
+// Redacted replaces secrets in recorded interactions.
+const Redacted = "REDACTED"
+
+type Mode string
+
+const (
+	// ModeReplay answers from the cassette and fails on any request it
+	// doesn't hold. It is the default.
+	ModeReplay Mode = "replay"
+	// ModeRecord sends requests upstream and saves them to the cassette.
+	ModeRecord Mode = "record"
+)
+
+var ErrUnexpectedRequest = errors.New("cassette: unexpected request")
+
+// ModeFromEnv returns ModeRecord when CASSETTE_MODE=record and ModeReplay
+// otherwise.
+func ModeFromEnv() Mode {
+	if Mode(os.Getenv(ModeEnv)) == ModeRecord {
+		return ModeRecord
+	}
+
+	return ModeReplay
+}
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// droppedHeaders change on every call and would only add noise to diffs of
+// re-recorded cassettes.
+var droppedHeaders = []string{"Set-Cookie", "Date"}
+
+type options struct {
+	mode         Mode
+	transport    http.RoundTripper
+	secretParams []string
+	onUnexpected func(Request)
+}
+
+// Option customises a Cassette.
+type Option func(*options)
+
+// WithMode overrides the mode read from CASSETTE_MODE.
+func WithMode(mode Mode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// WithTransport sets the transport used to reach the upstream while
+// recording, http.DefaultTransport by default.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = rt
+	}
+}
+
+// WithSecretParams lists query parameters whose values are secrets, "key" by
+// default. Their values are redacted from recorded URLs and bodies and
+// ignored when matching requests on replay.
+func WithSecretParams(names ...string) Option {
+	return func(o *options) {
+		o.secretParams = names
+	}
+}
+
+// OnUnexpected is called with every request a replaying cassette can't
+// answer, e.g. to fail the running test.
+func OnUnexpected(fn func(Request)) Option {
+	return func(o *options) {
+		o.onUnexpected = fn
+	}
+}
+
+// Cassette is an http.RoundTripper that records to or replays from a JSON
+// file. Interactions are replayed in order, each at most once.
+type Cassette struct {
+	path         string
+	opts         options
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Load opens the cassette at path. In replay mode the file must exist; in
+// record mode it is written by Save.
+func Load(path string, opts ...Option) (*Cassette, error) {
+	o := options{
+		mode:         ModeFromEnv(),
+		transport:    http.DefaultTransport,
+		secretParams: []string{"key"},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &Cassette{path: path, opts: o}
+	if o.mode == ModeRecord {
+		return c, nil
+	}
+
+	raw, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return nil, readErr
+	}
+	if unmErr := json.Unmarshal(raw, &c.interactions); unmErr != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, unmErr)
+	}
+	c.used = make([]bool, len(c.interactions))
+
+	return c, nil
+}
+
+func (c *Cassette) Mode() Mode {
+	return c.opts.mode
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c.opts.mode == ModeRecord {
+		return c.record(req)
+	}
+
+	return c.replay(req)
+}
+
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	wanted := Request{Method: req.Method, URL: c.sanitizeURL(req.URL)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, interaction := range c.interactions {
+		if c.used[i] || interaction.Request != wanted {
+			continue
+		}
+		c.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	if c.opts.onUnexpected != nil {
+		c.opts.onUnexpected(wanted)
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrUnexpectedRequest, wanted.Method, wanted.URL)
+}
+
+func (c *Cassette) record(req *http.Request) (*http.Response, error) {
+	resp, rtErr := c.opts.transport.RoundTrip(req)
+	if rtErr != nil {
+		return nil, rtErr
+	}
+	defer resp.Body.Close()
+
+	body, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil, readErr
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	for _, name := range droppedHeaders {
+		header.Del(name)
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, Interaction{
+		Request: Request{Method: req.Method, URL: c.sanitizeURL(req.URL)},
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: header,
+			Body:   c.sanitizeBody(req.URL, string(body)),
+		},
+	})
+	c.used = append(c.used, true)
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes the recorded interactions. It does nothing when replaying.
+func (c *Cassette) Save() error {
+	if c.opts.mode != ModeRecord {
+		return nil
+	}
+
+	c.mu.Lock()
+	raw, marshErr := json.MarshalIndent(c.interactions, "", "  ")
+	c.mu.Unlock()
+	if marshErr != nil {
+		return marshErr
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, append(raw, '\n'), 0o644)
+}
+
+// Unused returns the replayable interactions no request asked for.
+func (c *Cassette) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range c.interactions {
+		if !c.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+
+	return unused
+}
+
+func (c *Cassette) sanitizeURL(u *url.URL) string {
+	sanitized := *u
+	query := sanitized.Query()
+	for _, name := range c.opts.secretParams {
+		if query.Has(name) {
+			query.Set(name, Redacted)
+		}
+	}
+	sanitized.RawQuery = query.Encode()
+
+	return sanitized.String()
+}
+
+func (c *Cassette) sanitizeBody(u *url.URL, body string) string {
+	query := u.Query()
+	for _, name := range c.opts.secretParams {
+		if secret := query.Get(name); secret != "" {
+			body = strings.ReplaceAll(body, secret, Redacted)
+		}
+	}
+
+	return body
+}
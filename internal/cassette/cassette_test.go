@@ -0,0 +1,101 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string, error) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, readErr := io.ReadAll(resp.Body)
+	require.NoError(t, readErr)
+
+	return resp.StatusCode, string(body), nil
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=1")
+		if r.URL.Query().Get("q") == "missing" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write([]byte(`{"q": "` + r.URL.Query().Get("q") + `", "echo": "` + r.URL.Query().Get("key") + `"}`))
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, loadErr := Load(path, WithMode(ModeRecord))
+	require.NoError(t, loadErr)
+	client := &http.Client{Transport: recorder}
+
+	status, body, err := get(t, client, upstream.URL+"/v1/current.json?key=s3cr3t&q=Gramado")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "s3cr3t", "the live response is not altered")
+
+	status, _, err = get(t, client, upstream.URL+"/v1/current.json?key=s3cr3t&q=missing")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, status)
+	require.NoError(t, recorder.Save())
+	assert.Equal(t, 2, calls)
+
+	raw, readErr := os.ReadFile(path)
+	require.NoError(t, readErr)
+	assert.NotContains(t, string(raw), "s3cr3t")
+	assert.NotContains(t, string(raw), "Set-Cookie")
+	assert.Contains(t, string(raw), "key=REDACTED")
+
+	var unexpected []Request
+	player, loadErr := Load(path, WithMode(ModeReplay), OnUnexpected(func(req Request) {
+		unexpected = append(unexpected, req)
+	}))
+	require.NoError(t, loadErr)
+	client = &http.Client{Transport: player}
+
+	status, body, err = get(t, client, upstream.URL+"/v1/current.json?key=another-key&q=missing")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{"q": "missing", "echo": "REDACTED"}`, body)
+	assert.Len(t, player.Unused(), 1)
+
+	status, _, err = get(t, client, upstream.URL+"/v1/current.json?key=another-key&q=Gramado")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, player.Unused())
+	assert.Equal(t, 2, calls, "replaying must not reach the upstream")
+
+	_, _, err = get(t, client, upstream.URL+"/v1/current.json?key=another-key&q=Gramado")
+	assert.ErrorIs(t, err, ErrUnexpectedRequest, "each interaction is replayed once")
+	require.Len(t, unexpected, 1)
+	assert.Equal(t, upstream.URL+"/v1/current.json?key=REDACTED&q=Gramado", unexpected[0].URL)
+}
+
+func TestReplayRequiresCassette(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"), WithMode(ModeReplay))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(ModeEnv, "record")
+	assert.Equal(t, ModeRecord, ModeFromEnv())
+
+	t.Setenv(ModeEnv, "")
+	assert.Equal(t, ModeReplay, ModeFromEnv())
+}
@@ -0,0 +1,25 @@
+package cassette
+
+import "testing"
+
+// ForTest loads the cassette at path for t: unexpected requests fail the test
+// and, when recording, the cassette is saved once the test ends.
+func ForTest(t testing.TB, path string, opts ...Option) *Cassette {
+	t.Helper()
+
+	opts = append(opts, OnUnexpected(func(req Request) {
+		t.Errorf("cassette %s has no interaction for %s %s", path, req.Method, req.URL)
+	}))
+	c, loadErr := Load(path, opts...)
+	if loadErr != nil {
+		t.Fatalf("loading cassette: %s", loadErr)
+	}
+
+	t.Cleanup(func() {
+		if err := c.Save(); err != nil {
+			t.Errorf("saving cassette %s: %s", path, err)
+		}
+	})
+
+	return c
+}
@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Client calls service B's GET /temperature.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithRoundTripper sends the client's requests through rt, such as a
+// transport serving service B's handler in-process.
+func WithRoundTripper(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// NewClient talks to service B at host, which may be a bare host:port, as in
+// the config, or a full URL.
+func NewClient(host string, opts ...ClientOption) *Client {
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+
+	client := &Client{
+		baseURL: strings.TrimSuffix(host, "/"),
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+// Temperature returns service B's answer, or the entity error it responded
+// with.
+func (c *Client) Temperature(ctx context.Context, req TemperatureRequest) (TemperatureResponse, error) {
+	query := url.Values{}
+	query.Set(QueryCEP, req.CEP)
+	if req.Units != "" {
+		query.Set(QueryUnits, req.Units)
+	}
+
+	httpReq, reqErr := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		c.baseURL+TemperaturePath+"?"+query.Encode(),
+		nil,
+	)
+	if reqErr != nil {
+		return TemperatureResponse{}, reqErr
+	}
+
+	if req.Language != "" {
+		httpReq.Header.Set("Accept-Language", req.Language)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
+
+	resp, doErr := c.httpClient.Do(httpReq)
+	if doErr != nil {
+		return TemperatureResponse{}, doErr
+	}
+	defer resp.Body.Close()
+
+	body, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return TemperatureResponse{}, readErr
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return TemperatureResponse{}, ErrorFromResponse(resp.StatusCode, body)
+	}
+
+	var out TemperatureResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return TemperatureResponse{}, err
+	}
+
+	return out, nil
+}
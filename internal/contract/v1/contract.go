@@ -0,0 +1,84 @@
+// Package v1 is version 1 of the HTTP contract of both services: the bodies
+// service A accepts and returns on POST /getCep and its GET alternatives, and
+// the query and response of service B's GET /temperature. Both sides encode
+// and decode these types, so a change to the wire format has to happen here,
+// where the compatibility test guards it.
+package v1
+
+import "time"
+
+const Version = "v1"
+
+const (
+	// GetCepPath is service A's endpoint, taking a CEPRequest body.
+	GetCepPath = "/getCep"
+
+	// TemperaturePath is service B's endpoint, taking the CEP and units as
+	// query parameters and the language in Accept-Language. Service A serves
+	// it too, for clients that can only send GET requests.
+	TemperaturePath = "/temperature"
+	QueryCEP        = "cep"
+	QueryUnits      = "units"
+
+	// TemperatureByCEPPath is service A's GET endpoint taking the CEP as the
+	// PathCEP path value.
+	TemperatureByCEPPath = "/temperature/{cep}"
+	PathCEP              = "cep"
+)
+
+// CEPRequest is the body of POST /getCep.
+type CEPRequest struct {
+	CEP string `json:"cep"`
+}
+
+// CEPResponse is service A's answer. Only the requested units are present.
+type CEPResponse struct {
+	City      string   `json:"city"`
+	Condition string   `json:"condition,omitempty"`
+	TempC     *float64 `json:"temp_C,omitempty"`
+	TempF     *float64 `json:"temp_F,omitempty"`
+	TempK     *float64 `json:"temp_K,omitempty"`
+	TempR     *float64 `json:"temp_R,omitempty"`
+	TempRe    *float64 `json:"temp_Re,omitempty"`
+
+	// LastUpdated is when the weather provider last refreshed the reading.
+	LastUpdated *time.Time `json:"last_updated,omitempty"`
+}
+
+// TemperatureRequest is a lookup on service B for a canonical CEP. Units is a
+// comma separated list of unit codes and Language a BCP 47 tag, empty
+// meaning service B's defaults.
+type TemperatureRequest struct {
+	CEP      string
+	Units    string
+	Language string
+}
+
+// TemperatureResponse is service B's answer. Only the requested units are
+// present.
+type TemperatureResponse struct {
+	Location  string   `json:"location"`
+	Condition string   `json:"condition,omitempty"`
+	TempC     *float64 `json:"temp_C,omitempty"`
+	TempF     *float64 `json:"temp_F,omitempty"`
+	TempK     *float64 `json:"temp_K,omitempty"`
+	TempR     *float64 `json:"temp_R,omitempty"`
+	TempRe    *float64 `json:"temp_Re,omitempty"`
+
+	// LastUpdated is when the weather provider last refreshed the reading.
+	LastUpdated *time.Time `json:"last_updated,omitempty"`
+}
+
+// CEPResponse builds service A's answer from service B's.
+func (r TemperatureResponse) CEPResponse() CEPResponse {
+	return CEPResponse{
+		City:        r.Location,
+		Condition:   r.Condition,
+		TempC:       r.TempC,
+		TempF:       r.TempF,
+		TempK:       r.TempK,
+		TempR:       r.TempR,
+		TempRe:      r.TempRe,
+		LastUpdated: r.LastUpdated,
+	}
+}
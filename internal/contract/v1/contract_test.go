@@ -0,0 +1,215 @@
+package v1_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/openapi"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/web"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase/usecasetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWireCompatibility decodes payloads in the v1 wire format strictly and
+// encodes them back, so renaming, retyping or dropping a field fails here
+// instead of silently breaking the other service.
+func TestWireCompatibility(t *testing.T) {
+	tests := []struct {
+		file  string
+		value any
+	}{
+		{"cep_request.json", &contract.CEPRequest{}},
+		{"cep_response.json", &contract.CEPResponse{}},
+		{"temperature_response.json", &contract.TemperatureResponse{}},
+		{"error_response.json", &contract.ErrorResponse{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			golden, readErr := os.ReadFile(filepath.Join("testdata", tt.file))
+			require.NoError(t, readErr)
+
+			decoder := json.NewDecoder(bytes.NewReader(golden))
+			decoder.DisallowUnknownFields()
+			require.NoError(t, decoder.Decode(tt.value))
+
+			encoded, err := json.Marshal(tt.value)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(golden), string(encoded))
+		})
+	}
+}
+
+func TestCEPResponseFromTemperatureResponse(t *testing.T) {
+	golden, readErr := os.ReadFile(filepath.Join("testdata", "temperature_response.json"))
+	require.NoError(t, readErr)
+	var fromB contract.TemperatureResponse
+	require.NoError(t, json.Unmarshal(golden, &fromB))
+
+	encoded, err := json.Marshal(fromB.CEPResponse())
+	require.NoError(t, err)
+
+	expected, readErr := os.ReadFile(filepath.Join("testdata", "cep_response.json"))
+	require.NoError(t, readErr)
+	assert.JSONEq(t, string(expected), string(encoded))
+}
+
+// TestClientAgainstServiceB runs the typed client against service B's real
+// handler, covering the successful answer and every error it can send.
+func TestClientAgainstServiceB(t *testing.T) {
+	spec, specErr := openapi.ServiceB()
+	require.NoError(t, specErr)
+
+	gw := usecase.NewGetWeather(
+		usecasetest.Locations{
+			"95670084": "Gramado",
+			"01001000": "Quota",
+			"20040020": "Down",
+			"30130010": "Key",
+			"70040010": "Nowhere",
+			"80010000": "ViaCEP",
+		},
+		usecasetest.Temperatures{
+			"Quota":   fmt.Errorf("%w: code 2007", entity.ErrWeatherQuotaExceeded),
+			"Down":    fmt.Errorf("%w: status 500", entity.ErrWeatherUnavailable),
+			"Key":     fmt.Errorf("%w: code 2006", entity.ErrWeatherAPIKeyInvalid),
+			"Nowhere": fmt.Errorf("%w: code 1006", entity.ErrWeatherLocationNotFound),
+			"ViaCEP":  fmt.Errorf("%w: status 429", entity.ErrCEPProviderRateLimited),
+		},
+		nil,
+	)
+	server := httptest.NewServer((&web.Server{GetWeather: gw, OpenAPI: spec}).Handler())
+	defer server.Close()
+
+	client := contract.NewClient(server.URL)
+
+	out, err := client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "95670084", Units: "C,Re", Language: "pt-BR"})
+	require.NoError(t, err)
+	assert.Equal(t, "Gramado", out.Location)
+	assert.Equal(t, "Ensolarado", out.Condition)
+	require.NotNil(t, out.TempC)
+	require.NotNil(t, out.TempRe)
+	assert.Equal(t, 25.0, *out.TempC)
+	assert.Equal(t, 20.0, *out.TempRe)
+	assert.Nil(t, out.TempF)
+
+	tests := []struct {
+		req    contract.TemperatureRequest
+		errIs  error
+		status int
+	}{
+		{contract.TemperatureRequest{CEP: "123"}, entity.ErrCEPNotValid, http.StatusUnprocessableEntity},
+		{contract.TemperatureRequest{CEP: "95670084", Units: "X"}, entity.ErrUnitNotValid, http.StatusBadRequest},
+		{contract.TemperatureRequest{CEP: "99999999"}, entity.ErrCEPNotFound, http.StatusNotFound},
+		{contract.TemperatureRequest{CEP: "80010000"}, entity.ErrCEPProviderRateLimited, http.StatusServiceUnavailable},
+		{contract.TemperatureRequest{CEP: "70040010"}, entity.ErrWeatherLocationNotFound, http.StatusNotFound},
+		{contract.TemperatureRequest{CEP: "30130010"}, entity.ErrWeatherAPIKeyInvalid, http.StatusBadGateway},
+		{contract.TemperatureRequest{CEP: "01001000"}, entity.ErrWeatherQuotaExceeded, http.StatusServiceUnavailable},
+		{contract.TemperatureRequest{CEP: "20040020"}, entity.ErrWeatherUnavailable, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.errIs.Error(), func(t *testing.T) {
+			_, err := client.Temperature(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.errIs)
+			assert.Equal(t, tt.status, contract.StatusFor(err))
+		})
+	}
+}
+
+func TestErrorFromResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		errIs  error
+		err    string
+	}{
+		{
+			name:   "known code",
+			status: http.StatusNotFound,
+			body:   `{"code":"cep_not_found","message":"can not found zipcode"}`,
+			errIs:  entity.ErrCEPNotFound,
+		},
+		{
+			name:   "wrapped message",
+			status: http.StatusServiceUnavailable,
+			body:   `{"code":"weather_unavailable","message":"upstream timed out"}`,
+			errIs:  entity.ErrWeatherUnavailable,
+		},
+		{
+			name:   "status disagreeing with the code",
+			status: http.StatusInternalServerError,
+			body:   `{"code":"cep_not_found","message":"can not found zipcode"}`,
+			err:    "unexpected status 500: can not found zipcode",
+		},
+		{
+			name:   "internal code",
+			status: http.StatusInternalServerError,
+			body:   `{"code":"internal","message":"boom"}`,
+			err:    "unexpected status 500: boom",
+		},
+		{
+			name:   "message without a code",
+			status: http.StatusNotFound,
+			body:   entity.ErrCEPNotFound.Error() + "\n",
+			err:    "unexpected status 404: can not found zipcode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := contract.ErrorFromResponse(tt.status, []byte(tt.body))
+			if tt.errIs != nil {
+				assert.ErrorIs(t, err, tt.errIs)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+			assert.Equal(t, http.StatusInternalServerError, contract.StatusFor(err))
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	contract.WriteError(rec, fmt.Errorf("%w: status 429", entity.ErrCEPProviderRateLimited))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"code":"cep_provider_rate_limited","message":"zipcode provider rate limit exceeded: status 429"}`, rec.Body.String())
+	assert.ErrorIs(t, contract.ErrorFromResponse(rec.Code, rec.Body.Bytes()), entity.ErrCEPProviderRateLimited)
+}
+
+func TestErrorCodesRoundTrip(t *testing.T) {
+	for _, err := range []error{
+		entity.ErrCEPNotFound,
+		entity.ErrCEPNotValid,
+		entity.ErrEmptyAPIkey,
+		entity.ErrUnitNotValid,
+		entity.ErrCEPProviderBadRequest,
+		entity.ErrCEPProviderUnavailable,
+		entity.ErrCEPProviderRateLimited,
+		entity.ErrWeatherLocationNotFound,
+		entity.ErrWeatherAPIKeyInvalid,
+		entity.ErrWeatherQuotaExceeded,
+		entity.ErrWeatherUnavailable,
+	} {
+		code := contract.ErrorCode(err)
+		assert.NotEqual(t, contract.CodeInternal, code, err.Error())
+		assert.Same(t, err, contract.ErrorForCode(code), code)
+	}
+	assert.Equal(t, contract.CodeInternal, contract.ErrorCode(errors.New("boom")))
+	assert.Nil(t, contract.ErrorForCode(contract.CodeInternal))
+}
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+)
+
+// Error codes name the entity errors on the wire, in ErrorResponse and in the
+// gRPC error details, so they can be recovered without matching messages.
+const (
+	CodeCEPNotFound             = "cep_not_found"
+	CodeCEPNotValid             = "cep_not_valid"
+	CodeEmptyAPIKey             = "empty_api_key"
+	CodeUnitNotValid            = "unit_not_valid"
+	CodeCEPProviderBadRequest   = "cep_provider_bad_request"
+	CodeCEPProviderUnavailable  = "cep_provider_unavailable"
+	CodeCEPProviderRateLimited  = "cep_provider_rate_limited"
+	CodeWeatherLocationNotFound = "weather_location_not_found"
+	CodeWeatherAPIKeyInvalid    = "weather_api_key_invalid"
+	CodeWeatherQuotaExceeded    = "weather_quota_exceeded"
+	CodeWeatherUnavailable      = "weather_unavailable"
+
+	// CodeInternal is sent for every error that isn't an entity error.
+	CodeInternal = "internal"
+)
+
+var errorCodes = []struct {
+	code string
+	err  error
+}{
+	{CodeCEPNotFound, entity.ErrCEPNotFound},
+	{CodeCEPNotValid, entity.ErrCEPNotValid},
+	{CodeEmptyAPIKey, entity.ErrEmptyAPIkey},
+	{CodeUnitNotValid, entity.ErrUnitNotValid},
+	{CodeCEPProviderBadRequest, entity.ErrCEPProviderBadRequest},
+	{CodeCEPProviderUnavailable, entity.ErrCEPProviderUnavailable},
+	{CodeCEPProviderRateLimited, entity.ErrCEPProviderRateLimited},
+	{CodeWeatherLocationNotFound, entity.ErrWeatherLocationNotFound},
+	{CodeWeatherAPIKeyInvalid, entity.ErrWeatherAPIKeyInvalid},
+	{CodeWeatherQuotaExceeded, entity.ErrWeatherQuotaExceeded},
+	{CodeWeatherUnavailable, entity.ErrWeatherUnavailable},
+}
+
+// ErrorResponse is the body both services answer errors with.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ErrorCode returns the code of the entity error err wraps, or CodeInternal.
+func ErrorCode(err error) string {
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			return known.code
+		}
+	}
+
+	return CodeInternal
+}
+
+// ErrorForCode returns the entity error named by code, or nil for unknown
+// codes and CodeInternal.
+func ErrorForCode(code string) error {
+	for _, known := range errorCodes {
+		if known.code == code {
+			return known.err
+		}
+	}
+
+	return nil
+}
+
+// StatusFor is the HTTP status both services answer err with, alongside the
+// ErrorResponse written by WriteError.
+func StatusFor(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrUnitNotValid):
+		return http.StatusBadRequest
+	case errors.Is(err, entity.ErrCEPNotValid):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, entity.ErrCEPNotFound),
+		errors.Is(err, entity.ErrWeatherLocationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrCEPProviderBadRequest),
+		errors.Is(err, entity.ErrWeatherAPIKeyInvalid):
+		return http.StatusBadGateway
+	case errors.Is(err, entity.ErrCEPProviderRateLimited),
+		errors.Is(err, entity.ErrCEPProviderUnavailable),
+		errors.Is(err, entity.ErrWeatherQuotaExceeded),
+		errors.Is(err, entity.ErrWeatherUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// WriteError answers err with its StatusFor status and an ErrorResponse
+// holding its code and message.
+func WriteError(writer http.ResponseWriter, err error) {
+	body, _ := json.Marshal(ErrorResponse{Code: ErrorCode(err), Message: err.Error()})
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(StatusFor(err))
+	_, _ = writer.Write(body)
+}
+
+// ParseErrorResponse decodes the ErrorResponse in body, returning false when
+// body isn't one.
+func ParseErrorResponse(body []byte) (ErrorResponse, bool) {
+	var resp ErrorResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Code == "" {
+		return ErrorResponse{}, false
+	}
+
+	return resp, true
+}
+
+// ErrorFromResponse recovers the entity error named by the code of an error
+// response, as long as status is the one the error is answered with, or
+// describes the response otherwise.
+func ErrorFromResponse(status int, body []byte) error {
+	resp, ok := ParseErrorResponse(body)
+	if !ok {
+		return fmt.Errorf("unexpected status %d: %s", status, strings.TrimSpace(string(body)))
+	}
+
+	if known := ErrorForCode(resp.Code); known != nil && StatusFor(known) == status {
+		return known
+	}
+
+	return fmt.Errorf("unexpected status %d: %s", status, resp.Message)
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+const (
+	// ClientIDBaggageKey carries the client authenticated by service A to
+	// service B.
+	ClientIDBaggageKey = "client.id"
+
+	// ClientIDUnverifiedKey is the span attribute service B records the
+	// ClientIDBaggageKey under. Service B can't tell service A apart from any
+	// other caller setting the baggage, so the value is only a hint for
+	// reading traces, never an identity to authorise or bill on.
+	ClientIDUnverifiedKey = attribute.Key("client.id.unverified")
+)
+
+// UnverifiedClientID returns the client.id baggage of ctx as a
+// ClientIDUnverifiedKey attribute.
+func UnverifiedClientID(ctx context.Context) (attribute.KeyValue, bool) {
+	id := baggage.FromContext(ctx).Member(ClientIDBaggageKey).Value()
+	if id == "" {
+		return attribute.KeyValue{}, false
+	}
+
+	return ClientIDUnverifiedKey.String(id), true
+}
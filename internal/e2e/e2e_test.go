@@ -0,0 +1,385 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/fakeupstream"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/serviceb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// localDevKey hashes to the key in env.test.json.
+const localDevKey = "local-dev-key"
+
+func getCep(t *testing.T, stack *Stack, cep, query string, header http.Header) (*http.Response, string) {
+	t.Helper()
+
+	req, reqErr := http.NewRequest(http.MethodPost, stack.ServiceA.URL+"/getCep"+query, strings.NewReader(`{"cep": "`+cep+`"}`))
+	require.NoError(t, reqErr)
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, doErr := http.DefaultClient.Do(req)
+	require.NoError(t, doErr)
+	defer resp.Body.Close()
+
+	body, readErr := io.ReadAll(resp.Body)
+	require.NoError(t, readErr)
+
+	return resp, string(body)
+}
+
+func get(t *testing.T, stack *Stack, path string, header http.Header) (*http.Response, string) {
+	t.Helper()
+
+	req, reqErr := http.NewRequest(http.MethodGet, stack.ServiceA.URL+path, nil)
+	require.NoError(t, reqErr)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, doErr := http.DefaultClient.Do(req)
+	require.NoError(t, doErr)
+	defer resp.Body.Close()
+
+	body, readErr := io.ReadAll(resp.Body)
+	require.NoError(t, readErr)
+
+	return resp, string(body)
+}
+
+func mustFind(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	span, ok := Find(spans, name)
+	require.True(t, ok, "span %q missing, got %v", name, Names(spans))
+	return span
+}
+
+func assertChildOf(t *testing.T, child, parent tracetest.SpanStub) {
+	t.Helper()
+
+	assert.Equal(t, parent.SpanContext.TraceID(), child.SpanContext.TraceID(), "%s is not in %s's trace", child.Name, parent.Name)
+	assert.Equal(t, parent.SpanContext.SpanID(), child.Parent.SpanID(), "%s is not a child of %s", child.Name, parent.Name)
+}
+
+func attributeValue(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestSuccess(t *testing.T) {
+	stack := Start(t)
+
+	resp, body := getCep(t, stack, "95670-084", "?units=C,R", http.Header{"Accept-Language": {"pt-BR"}})
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Equal(t, "pt", resp.Header.Get("Content-Language"))
+
+	var out contract.CEPResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &out))
+	assert.Equal(t, "Gramado", out.City)
+	require.NotNil(t, out.TempC)
+	require.NotNil(t, out.TempR)
+	assert.Equal(t, 14.0, *out.TempC)
+	assert.Equal(t, 516.87, *out.TempR)
+	assert.Nil(t, out.TempF)
+
+	spans := stack.Spans(t, ServiceASpan, ServiceBSpan, ServiceBCEPSpan, ServiceBWeatherSpan)
+	assert.Len(t, spans, 4, Names(spans))
+
+	spanA := mustFind(t, spans, ServiceASpan)
+	spanB := mustFind(t, spans, ServiceBSpan)
+	cepSpan := mustFind(t, spans, ServiceBCEPSpan)
+	weatherSpan := mustFind(t, spans, ServiceBWeatherSpan)
+
+	assert.False(t, spanA.Parent.IsValid(), "service A's span should be the root")
+	assertChildOf(t, spanB, spanA)
+	assertChildOf(t, cepSpan, spanB)
+	assertChildOf(t, weatherSpan, spanB)
+
+	for _, span := range []tracetest.SpanStub{spanA, spanB} {
+		assert.Contains(t, span.Attributes, attribute.String("temperature.units", "C,R"), span.Name)
+		assert.Contains(t, span.Attributes, attribute.String("temperature.language", "pt"), span.Name)
+	}
+
+	fullURL, ok := attributeValue(weatherSpan, semconv.URLFullKey)
+	require.True(t, ok)
+	assert.Contains(t, fullURL.AsString(), "lang=pt")
+	assert.NotContains(t, fullURL.AsString(), "fake-key")
+}
+
+// gramadoLastUpdated is the last_updated_epoch of Gramado's weather fixture.
+var gramadoLastUpdated = time.Unix(1715342100, 0).UTC()
+
+func TestGETRoutes(t *testing.T) {
+	stack := Start(t)
+
+	_, postBody := getCep(t, stack, "95670-084", "?units=C", nil)
+	var out contract.CEPResponse
+	require.NoError(t, json.Unmarshal([]byte(postBody), &out))
+	require.NotNil(t, out.LastUpdated)
+	assert.Equal(t, gramadoLastUpdated, *out.LastUpdated)
+
+	byPath, pathBody := get(t, stack, "/temperature/95670-084?units=C", nil)
+	require.Equal(t, http.StatusOK, byPath.StatusCode, pathBody)
+	assert.JSONEq(t, postBody, pathBody)
+	assert.Equal(t, "application/json", byPath.Header.Get("Content-Type"))
+	assert.Equal(t, gramadoLastUpdated.Format(http.TimeFormat), byPath.Header.Get("Last-Modified"))
+	assert.Equal(t, "public, max-age=0", byPath.Header.Get("Cache-Control"))
+	assert.Equal(t, "Accept-Language", byPath.Header.Get("Vary"))
+	etag := byPath.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	byQuery, queryBody := get(t, stack, "/temperature?cep=95670084&units=C", nil)
+	require.Equal(t, http.StatusOK, byQuery.StatusCode, queryBody)
+	assert.Equal(t, etag, byQuery.Header.Get("ETag"))
+
+	translated, _ := get(t, stack, "/temperature/95670084?units=C", http.Header{"Accept-Language": {"pt-BR"}})
+	assert.NotEqual(t, etag, translated.Header.Get("ETag"))
+
+	conditional := []struct {
+		name   string
+		header http.Header
+		status int
+	}{
+		{"matching ETag", http.Header{"If-None-Match": {`"stale", ` + etag}}, http.StatusNotModified},
+		{"other ETag", http.Header{"If-None-Match": {`"stale"`}}, http.StatusOK},
+		{"not refreshed since", http.Header{"If-Modified-Since": {gramadoLastUpdated.Format(http.TimeFormat)}}, http.StatusNotModified},
+		{"refreshed since", http.Header{"If-Modified-Since": {gramadoLastUpdated.Add(-time.Minute).Format(http.TimeFormat)}}, http.StatusOK},
+		{"If-None-Match wins", http.Header{"If-None-Match": {`"stale"`}, "If-Modified-Since": {gramadoLastUpdated.Format(http.TimeFormat)}}, http.StatusOK},
+	}
+	for _, tt := range conditional {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := get(t, stack, "/temperature/95670084?units=C", tt.header)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, etag, resp.Header.Get("ETag"))
+			if tt.status == http.StatusNotModified {
+				assert.Empty(t, body)
+			}
+		})
+	}
+
+	invalid, _ := get(t, stack, "/temperature/123", nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, invalid.StatusCode)
+	missing, _ := get(t, stack, "/temperature", nil)
+	assert.Equal(t, http.StatusBadRequest, missing.StatusCode)
+	unknown, _ := get(t, stack, "/temperature/95670999", nil)
+	assert.Equal(t, http.StatusNotFound, unknown.StatusCode)
+	assert.Empty(t, unknown.Header.Get("ETag"))
+}
+
+func TestGRPCTransport(t *testing.T) {
+	stack := Start(t, WithTransport(serviceb.TransportGRPC))
+
+	resp, body := getCep(t, stack, "95670084", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	var out contract.CEPResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &out))
+	require.NotNil(t, out.LastUpdated)
+	assert.Equal(t, gramadoLastUpdated, *out.LastUpdated)
+
+	spans := stack.Spans(t, ServiceASpan, ServiceBSpan, ServiceBCEPSpan, ServiceBWeatherSpan)
+	spanA := mustFind(t, spans, ServiceASpan)
+	spanB := mustFind(t, spans, ServiceBSpan)
+
+	// otelgrpc adds client and server spans between both services.
+	assert.True(t, DescendsFrom(spans, spanB, spanA), Names(spans))
+	assertChildOf(t, mustFind(t, spans, ServiceBCEPSpan), spanB)
+	assertChildOf(t, mustFind(t, spans, ServiceBWeatherSpan), spanB)
+}
+
+func TestClientIdentityReachesServiceB(t *testing.T) {
+	for _, transport := range []string{serviceb.TransportHTTP, serviceb.TransportGRPC} {
+		t.Run(transport, func(t *testing.T) {
+			stack := Start(t, WithTransport(transport), WithConfig(func(cfg *config.Config) {
+				cfg.ServiceA.Auth = config.Auth{
+					Enabled: true,
+					Header:  "X-API-Key",
+					Keys: []config.APIKey{{
+						ID:   "local-dev",
+						Hash: "ed5a18fb8f807f996d649e379d3f35f39c543a91bdbf88c492f2ebd10d4df86c",
+					}},
+				}
+			}))
+
+			resp, body := getCep(t, stack, "95670084", "", nil)
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, body)
+
+			resp, body = getCep(t, stack, "95670084", "", http.Header{"X-Api-Key": {localDevKey}})
+			require.Equal(t, http.StatusOK, resp.StatusCode, body)
+
+			spans := stack.Spans(t, ServiceASpan, ServiceBSpan)
+			assert.Contains(t, mustFind(t, spans, ServiceASpan).Attributes, semconv.EnduserID("local-dev"))
+			spanB := mustFind(t, spans, ServiceBSpan)
+			assert.Contains(t, spanB.Attributes, contract.ClientIDUnverifiedKey.String("local-dev"))
+			assert.NotContains(t, spanB.Attributes, semconv.EnduserID("local-dev"), "service B can't verify the client")
+		})
+	}
+}
+
+func TestOfflineCEPFallback(t *testing.T) {
+	stack := Start(t, WithCEPFault(fakeupstream.Fault{ErrorRate: 1}))
+
+	resp, body := getCep(t, stack, "95670084", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	assert.Contains(t, body, `"city":"Gramado"`)
+
+	spans := stack.Spans(t, ServiceASpan, ServiceBSpan, ServiceBCEPSpan, ServiceBWeatherSpan)
+	spanB := mustFind(t, spans, ServiceBSpan)
+	require.Len(t, spanB.Events, 1)
+	assert.Equal(t, "offline CEP lookup", spanB.Events[0].Name)
+	assert.Contains(t, mustFind(t, spans, ServiceBCEPSpan).Attributes, attribute.String("cep.lookup.failure", "unavailable"))
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		cep     string
+		query   string
+		status  int
+		spans   []string
+		absent  []string
+		failure string
+	}{
+		{
+			name:   "invalid CEP",
+			cep:    "123",
+			status: http.StatusUnprocessableEntity,
+			spans:  []string{ServiceASpan},
+			absent: []string{ServiceBSpan},
+		},
+		{
+			name:   "invalid units",
+			cep:    "95670084",
+			query:  "?units=X",
+			status: http.StatusBadRequest,
+			absent: []string{ServiceBSpan},
+		},
+		{
+			name:   "CEP outside every known range",
+			cep:    "00000001",
+			status: http.StatusNotFound,
+			spans:  []string{ServiceASpan, ServiceBSpan},
+			absent: []string{ServiceBCEPSpan, ServiceBWeatherSpan},
+		},
+		{
+			name:    "CEP unknown to ViaCEP",
+			cep:     "95670999",
+			status:  http.StatusNotFound,
+			spans:   []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan},
+			absent:  []string{ServiceBWeatherSpan},
+			failure: "not_found",
+		},
+		{
+			name:    "ViaCEP unavailable without offline city",
+			opts:    []Option{WithCEPFault(fakeupstream.Fault{ErrorRate: 1})},
+			cep:     "96010000",
+			status:  http.StatusServiceUnavailable,
+			spans:   []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan},
+			absent:  []string{ServiceBWeatherSpan},
+			failure: "unavailable",
+		},
+		{
+			name:    "ViaCEP rate limited",
+			opts:    []Option{WithCEPFault(fakeupstream.Fault{ErrorRate: 1, Status: http.StatusTooManyRequests})},
+			cep:     "96010000",
+			status:  http.StatusServiceUnavailable,
+			spans:   []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan},
+			absent:  []string{ServiceBWeatherSpan},
+			failure: "rate_limited",
+		},
+		{
+			name:    "ViaCEP rejects the request",
+			opts:    []Option{WithCEPFault(fakeupstream.Fault{ErrorRate: 1, Status: http.StatusBadRequest})},
+			cep:     "96010000",
+			status:  http.StatusBadGateway,
+			spans:   []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan},
+			absent:  []string{ServiceBWeatherSpan},
+			failure: "bad_request",
+		},
+		{
+			name:   "weather location not found",
+			opts:   []Option{WithWeatherFault(fakeupstream.Fault{ErrorRate: 1, Status: http.StatusBadRequest})},
+			cep:    "95670084",
+			status: http.StatusNotFound,
+			spans:  []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan, ServiceBWeatherSpan},
+		},
+		{
+			name:   "weather API key rejected",
+			opts:   []Option{WithWeatherFault(fakeupstream.Fault{ErrorRate: 1, Status: http.StatusUnauthorized})},
+			cep:    "95670084",
+			status: http.StatusBadGateway,
+			spans:  []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan, ServiceBWeatherSpan},
+		},
+		{
+			name:   "weather quota exceeded",
+			opts:   []Option{WithWeatherFault(fakeupstream.Fault{ErrorRate: 1, Status: http.StatusForbidden})},
+			cep:    "95670084",
+			status: http.StatusServiceUnavailable,
+			spans:  []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan, ServiceBWeatherSpan},
+		},
+		{
+			name:   "weather unavailable",
+			opts:   []Option{WithWeatherFault(fakeupstream.Fault{ErrorRate: 1})},
+			cep:    "95670084",
+			status: http.StatusServiceUnavailable,
+			spans:  []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan, ServiceBWeatherSpan},
+		},
+		{
+			name: "weather API key missing",
+			opts: []Option{WithConfig(func(cfg *config.Config) {
+				cfg.Temperature.ApiKey = ""
+			})},
+			cep:    "95670084",
+			status: http.StatusInternalServerError,
+			spans:  []string{ServiceASpan, ServiceBSpan, ServiceBCEPSpan, ServiceBWeatherSpan},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stack := Start(t, tt.opts...)
+
+			resp, body := getCep(t, stack, tt.cep, tt.query, nil)
+			assert.Equal(t, tt.status, resp.StatusCode, body)
+
+			spans := stack.Spans(t, tt.spans...)
+			for _, name := range tt.absent {
+				_, found := Find(spans, name)
+				assert.False(t, found, "unexpected span %q", name)
+			}
+
+			if spanB, ok := Find(spans, ServiceBSpan); ok {
+				assertChildOf(t, spanB, mustFind(t, spans, ServiceASpan))
+				for _, span := range spans {
+					if span.Name == ServiceBCEPSpan || span.Name == ServiceBWeatherSpan {
+						assertChildOf(t, span, spanB)
+					}
+				}
+			}
+			if tt.failure != "" {
+				cepSpan := mustFind(t, spans, ServiceBCEPSpan)
+				assert.Contains(t, cepSpan.Attributes, attribute.String("cep.lookup.failure", tt.failure))
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Package e2e runs service A, service B and the fake upstreams in process,
+// exporting every span they create to memory so tests can assert on both the
+// HTTP responses and the resulting traces.
+package e2e
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/fakeupstream"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/opentel"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/serviceb"
+	"github.com/MatheusBenetti/opentelemetry/internal/services"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	ServiceASpan        = services.ServiceASpan
+	ServiceBSpan        = services.ServiceBSpan
+	ServiceBCEPSpan     = "service_b:get_CEP"
+	ServiceBWeatherSpan = "service_b:get_weather"
+)
+
+type options struct {
+	transport    string
+	cepFault     fakeupstream.Fault
+	weatherFault fakeupstream.Fault
+	configure    []func(*config.Config)
+}
+
+// Option customises the stack started by Start.
+type Option func(*options)
+
+// WithTransport picks how service A reaches service B, serviceb.TransportHTTP
+// by default.
+func WithTransport(transport string) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// WithCEPFault injects latency and errors into the fake ViaCEP.
+func WithCEPFault(fault fakeupstream.Fault) Option {
+	return func(o *options) {
+		o.cepFault = fault
+	}
+}
+
+// WithWeatherFault injects latency and errors into the fake weatherapi.com.
+func WithWeatherFault(fault fakeupstream.Fault) Option {
+	return func(o *options) {
+		o.weatherFault = fault
+	}
+}
+
+// WithConfig changes the config both services are built from, after the
+// upstream URLs and a fake API key were filled in.
+func WithConfig(fn func(*config.Config)) Option {
+	return func(o *options) {
+		o.configure = append(o.configure, fn)
+	}
+}
+
+// Stack is a running service A, service B and fake upstreams sharing one
+// in-memory span exporter.
+type Stack struct {
+	ServiceA  *httptest.Server
+	ServiceB  *httptest.Server
+	Upstreams *httptest.Server
+	Config    config.Config
+	exporter  *tracetest.InMemoryExporter
+}
+
+// Start brings the stack up and tears it down when the test ends. Both
+// services trace through the stack's own provider, but the propagator is
+// still the global one and is replaced for the duration of the test, so
+// tests using it must not run in parallel.
+func Start(t testing.TB, opts ...Option) *Stack {
+	t.Helper()
+
+	o := options{transport: serviceb.TransportHTTP}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	stack := &Stack{exporter: tracetest.NewInMemoryExporter()}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(stack.exporter))
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(opentel.Propagator())
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(previousPropagator)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	fixtures, fixErr := fakeupstream.EmbeddedFixtures()
+	require.NoError(t, fixErr)
+	stack.Upstreams = httptest.NewServer(fakeupstream.NewHandler(
+		fixtures,
+		fakeupstream.WithCEPFault(o.cepFault),
+		fakeupstream.WithWeatherFault(o.weatherFault),
+	))
+	t.Cleanup(stack.Upstreams.Close)
+
+	stack.Config = config.Config{
+		CEP:         config.CEP{URL: stack.Upstreams.URL},
+		Temperature: config.Temperature{URL: stack.Upstreams.URL, ApiKey: "fake-key"},
+	}
+	for _, fn := range o.configure {
+		fn(&stack.Config)
+	}
+
+	gw, gwErr := services.GetWeather(&stack.Config, provider)
+	require.NoError(t, gwErr)
+
+	serverB, serverBErr := services.ServiceB(&stack.Config, gw, provider)
+	require.NoError(t, serverBErr)
+	stack.ServiceB = httptest.NewServer(serverB.Handler())
+	t.Cleanup(stack.ServiceB.Close)
+	stack.Config.ServiceB.Host = stack.ServiceB.URL
+
+	var client serviceb.Client = contract.NewClient(stack.ServiceB.URL)
+	if o.transport == serviceb.TransportGRPC {
+		listener, listenErr := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, listenErr)
+		go func() {
+			_ = services.ServiceBGRPC(gw, provider).Serve(listener)
+		}()
+		t.Cleanup(func() { _ = listener.Close() })
+
+		grpcClient, dialErr := serviceb.NewGRPCClient(listener.Addr().String(), serviceb.WithTracerProvider(provider))
+		require.NoError(t, dialErr)
+		t.Cleanup(func() { _ = grpcClient.Close() })
+		client = grpcClient
+	}
+
+	serverA, serverAErr := services.ServiceA(&stack.Config, client, provider, nil)
+	require.NoError(t, serverAErr)
+	stack.ServiceA = httptest.NewServer(serverA.Handler())
+	t.Cleanup(stack.ServiceA.Close)
+
+	return stack
+}
+
+// Spans waits until a span with each of the given names has ended and returns
+// every span exported so far. Servers end their spans after writing the
+// response, so they may still be in flight when the client returns.
+func (s *Stack) Spans(t testing.TB, names ...string) tracetest.SpanStubs {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		spans := s.exporter.GetSpans()
+		missing := ""
+		for _, name := range names {
+			if _, ok := Find(spans, name); !ok {
+				missing = name
+				break
+			}
+		}
+		if missing == "" {
+			return spans
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("span %q was never exported, got %v", missing, Names(spans))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Reset drops the spans exported so far.
+func (s *Stack) Reset() {
+	s.exporter.Reset()
+}
+
+// Find returns the first span called name.
+func Find(spans tracetest.SpanStubs, name string) (tracetest.SpanStub, bool) {
+	for _, span := range spans {
+		if span.Name == name {
+			return span, true
+		}
+	}
+
+	return tracetest.SpanStub{}, false
+}
+
+// Names lists the spans' names in export order.
+func Names(spans tracetest.SpanStubs) []string {
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+
+	return names
+}
+
+// DescendsFrom reports whether child is parent or sits below it in the trace.
+func DescendsFrom(spans tracetest.SpanStubs, child, parent tracetest.SpanStub) bool {
+	for current := child; ; {
+		if current.SpanContext.Equal(parent.SpanContext) {
+			return true
+		}
+		next, ok := parentOf(spans, current)
+		if !ok {
+			return false
+		}
+		current = next
+	}
+}
+
+func parentOf(spans tracetest.SpanStubs, child tracetest.SpanStub) (tracetest.SpanStub, bool) {
+	if !child.Parent.IsValid() {
+		return tracetest.SpanStub{}, false
+	}
+	for _, span := range spans {
+		if span.SpanContext.SpanID() == child.Parent.SpanID() && span.SpanContext.TraceID() == child.Parent.TraceID() {
+			return span, true
+		}
+	}
+
+	return tracetest.SpanStub{}, false
+}
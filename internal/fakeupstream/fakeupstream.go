@@ -0,0 +1,157 @@
+// Package fakeupstream serves ViaCEP and weatherapi.com compatible responses
+// from fixture files, so the services can run and be tested without internet
+// access.
+package fakeupstream
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	viaCEPFixture  = "viacep.json"
+	weatherFixture = "weatherapi.json"
+)
+
+//go:embed fixtures/*.json
+var embedded embed.FS
+
+// Fixtures holds the canned answers: ViaCEP bodies keyed by CEP digits and
+// weatherapi.com bodies keyed by the lower-cased location name.
+type Fixtures struct {
+	CEPs    map[string]json.RawMessage
+	Weather map[string]json.RawMessage
+}
+
+// EmbeddedFixtures returns the fixtures shipped with the binary.
+func EmbeddedFixtures() (Fixtures, error) {
+	sub, subErr := fs.Sub(embedded, "fixtures")
+	if subErr != nil {
+		return Fixtures{}, subErr
+	}
+
+	return loadFixtures(sub, nil)
+}
+
+// LoadFixtures reads viacep.json and weatherapi.json from dir. A file missing
+// from dir is taken from the embedded fixtures instead.
+func LoadFixtures(dir string) (Fixtures, error) {
+	fallback, embErr := EmbeddedFixtures()
+	if embErr != nil {
+		return Fixtures{}, embErr
+	}
+	if dir == "" {
+		return fallback, nil
+	}
+
+	return loadFixtures(os.DirFS(filepath.Clean(dir)), &fallback)
+}
+
+func loadFixtures(fsys fs.FS, fallback *Fixtures) (Fixtures, error) {
+	var fixtures Fixtures
+	if err := readFixture(fsys, viaCEPFixture, &fixtures.CEPs); err != nil {
+		if fallback == nil || !errors.Is(err, fs.ErrNotExist) {
+			return Fixtures{}, err
+		}
+		fixtures.CEPs = fallback.CEPs
+	}
+	if err := readFixture(fsys, weatherFixture, &fixtures.Weather); err != nil {
+		if fallback == nil || !errors.Is(err, fs.ErrNotExist) {
+			return Fixtures{}, err
+		}
+		fixtures.Weather = fallback.Weather
+	}
+
+	return fixtures, nil
+}
+
+func readFixture(fsys fs.FS, name string, into *map[string]json.RawMessage) error {
+	raw, readErr := fs.ReadFile(fsys, name)
+	if readErr != nil {
+		return readErr
+	}
+
+	return json.Unmarshal(raw, into)
+}
+
+// Fault makes a fake upstream slow or unreliable. Every request waits for
+// Latency and then fails with Status with probability ErrorRate.
+type Fault struct {
+	Latency   time.Duration
+	ErrorRate float64
+	Status    int
+}
+
+// inject waits for the configured latency and reports whether the request
+// should fail. It returns false as well when the client gave up waiting.
+func (f Fault) inject(ctx context.Context) (fail bool, ok bool) {
+	if f.Latency > 0 {
+		select {
+		case <-time.After(f.Latency):
+		case <-ctx.Done():
+			return false, false
+		}
+	}
+
+	return f.ErrorRate > 0 && rand.Float64() < f.ErrorRate, true
+}
+
+func (f Fault) status() int {
+	if f.Status == 0 {
+		return http.StatusServiceUnavailable
+	}
+
+	return f.Status
+}
+
+type options struct {
+	cepFault     Fault
+	weatherFault Fault
+}
+
+// Option customises the fake upstreams.
+type Option func(*options)
+
+// WithCEPFault injects latency and errors into the ViaCEP endpoint.
+func WithCEPFault(fault Fault) Option {
+	return func(o *options) {
+		o.cepFault = fault
+	}
+}
+
+// WithWeatherFault injects latency and errors into the weatherapi.com
+// endpoint.
+func WithWeatherFault(fault Fault) Option {
+	return func(o *options) {
+		o.weatherFault = fault
+	}
+}
+
+// NewHandler serves both upstreams from the same origin, so config.CEP.URL
+// and config.Temperature.URL can point at one address.
+func NewHandler(fixtures Fixtures, opts ...Option) http.Handler {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /ws/{cep}/json", &viaCEP{ceps: fixtures.CEPs, fault: o.cepFault})
+	mux.Handle("GET /v1/current.json", &weatherAPI{locations: fixtures.Weather, fault: o.weatherFault})
+
+	return mux
+}
+
+func writeJSON(writer http.ResponseWriter, status int, body []byte) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_, _ = writer.Write(body)
+}
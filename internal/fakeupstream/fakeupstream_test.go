@@ -0,0 +1,107 @@
+package fakeupstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/dto"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/api"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUpstreams(t *testing.T, opts ...Option) string {
+	t.Helper()
+
+	fixtures, loadErr := EmbeddedFixtures()
+	require.NoError(t, loadErr)
+
+	server := httptest.NewServer(NewHandler(fixtures, opts...))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func TestGetWeatherAgainstFakeUpstreams(t *testing.T) {
+	url := newUpstreams(t)
+	gw := usecase.NewGetWeather(api.NewCEPFromAPI(url), api.NewWeatherFromAPI(url, api.StaticKey("fake")), nil)
+
+	out, err := gw.Execute(context.Background(), dto.LocationInput{CEP: "95670-084", Units: "C"})
+	require.NoError(t, err)
+	assert.Equal(t, "Gramado", out.Location)
+	assert.Equal(t, "Partly cloudy", out.Condition)
+	require.NotNil(t, out.TempC)
+	assert.Equal(t, 14.0, *out.TempC)
+
+	_, err = gw.Execute(context.Background(), dto.LocationInput{CEP: "99999999"})
+	assert.ErrorIs(t, err, entity.ErrCEPNotFound)
+}
+
+func TestCEPErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault Fault
+		cep   string
+		errIs error
+	}{
+		{name: "malformed CEP", cep: "1234", errIs: entity.ErrCEPProviderBadRequest},
+		{name: "unknown CEP", cep: "99999999", errIs: entity.ErrCEPNotFound},
+		{name: "injected outage", cep: "95670084", fault: Fault{ErrorRate: 1}, errIs: entity.ErrCEPProviderUnavailable},
+		{name: "injected throttling", cep: "95670084", fault: Fault{ErrorRate: 1, Status: http.StatusTooManyRequests}, errIs: entity.ErrCEPProviderRateLimited},
+		{name: "injected latency", cep: "95670084", fault: Fault{Latency: time.Second}, errIs: entity.ErrCEPProviderUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := newUpstreams(t, WithCEPFault(tt.fault))
+			repo := api.NewCEPFromAPI(url, api.WithTimeout(100*time.Millisecond))
+
+			_, err := repo.Get(context.Background(), tt.cep)
+			assert.ErrorIs(t, err, tt.errIs)
+		})
+	}
+}
+
+func TestWeatherErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fault    Fault
+		location string
+		errIs    error
+	}{
+		{name: "unknown location", location: "Atlantis", errIs: entity.ErrWeatherLocationNotFound},
+		{name: "injected invalid key", location: "Gramado", fault: Fault{ErrorRate: 1, Status: http.StatusUnauthorized}, errIs: entity.ErrWeatherAPIKeyInvalid},
+		{name: "injected quota", location: "Gramado", fault: Fault{ErrorRate: 1, Status: http.StatusForbidden}, errIs: entity.ErrWeatherQuotaExceeded},
+		{name: "injected outage", location: "Gramado", fault: Fault{ErrorRate: 1}, errIs: entity.ErrWeatherUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := newUpstreams(t, WithWeatherFault(tt.fault))
+
+			_, err := api.NewWeatherFromAPI(url, api.StaticKey("fake")).Get(context.Background(), tt.location, entity.DefaultLanguage)
+			assert.ErrorIs(t, err, tt.errIs)
+		})
+	}
+}
+
+func TestLoadFixturesFallsBackToEmbedded(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "viacep.json"), []byte(`{"12345678": {"cep": "12345-678", "localidade": "Gramado"}}`), 0o600))
+
+	fixtures, err := LoadFixtures(dir)
+	require.NoError(t, err)
+	assert.Len(t, fixtures.CEPs, 1)
+	assert.Contains(t, fixtures.Weather, "gramado")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "weatherapi.json"), []byte(`{`), 0o600))
+	_, err = LoadFixtures(dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package fakeupstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+var cepPattern = regexp.MustCompile(`^[0-9]{8}$`)
+
+// viaCEP mimics https://viacep.com.br/ws/{cep}/json: malformed CEPs get an
+// HTML 400 page and unknown ones a 200 with "erro".
+type viaCEP struct {
+	ceps  map[string]json.RawMessage
+	fault Fault
+}
+
+func (v *viaCEP) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	fail, ok := v.fault.inject(request.Context())
+	if !ok {
+		return
+	}
+	if fail {
+		writeHTML(writer, v.fault.status())
+		return
+	}
+
+	cep := request.PathValue("cep")
+	if !cepPattern.MatchString(cep) {
+		writeHTML(writer, http.StatusBadRequest)
+		return
+	}
+
+	body, found := v.ceps[cep]
+	if !found {
+		writeJSON(writer, http.StatusOK, []byte(`{"erro": "true"}`))
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, body)
+}
+
+func writeHTML(writer http.ResponseWriter, status int) {
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writer.WriteHeader(status)
+	_, _ = fmt.Fprintf(writer, "<!DOCTYPE html><html><body><h1>%d %s</h1></body></html>", status, http.StatusText(status))
+}
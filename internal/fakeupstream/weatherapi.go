@@ -0,0 +1,81 @@
+package fakeupstream
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/dto"
+)
+
+// weatherAPI mimics https://api.weatherapi.com/v1/current.json, answering
+// errors with weatherapi.com's error envelope and codes.
+type weatherAPI struct {
+	locations map[string]json.RawMessage
+	fault     Fault
+}
+
+func (w *weatherAPI) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	fail, ok := w.fault.inject(request.Context())
+	if !ok {
+		return
+	}
+	if fail {
+		status := w.fault.status()
+		writeWeatherError(writer, status, weatherErrorCode(status))
+		return
+	}
+
+	query := request.URL.Query()
+	if query.Get("key") == "" {
+		writeWeatherError(writer, http.StatusUnauthorized, 1002)
+		return
+	}
+
+	location := strings.ToLower(strings.TrimSpace(query.Get("q")))
+	if location == "" {
+		writeWeatherError(writer, http.StatusBadRequest, 1003)
+		return
+	}
+
+	body, found := w.locations[location]
+	if !found {
+		writeWeatherError(writer, http.StatusBadRequest, 1006)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, body)
+}
+
+var weatherErrorMessages = map[int]string{
+	1002: "API key is invalid or not provided.",
+	1003: "Parameter q is missing.",
+	1006: "No matching location found.",
+	2006: "API key provided is invalid",
+	2007: "API key has exceeded calls per month quota.",
+	9999: "Internal application error.",
+}
+
+// weatherErrorCode picks the code weatherapi.com sends along an injected
+// status.
+func weatherErrorCode(status int) int {
+	switch status {
+	case http.StatusBadRequest:
+		return 1006
+	case http.StatusUnauthorized:
+		return 2006
+	case http.StatusForbidden:
+		return 2007
+	default:
+		return 9999
+	}
+}
+
+func writeWeatherError(writer http.ResponseWriter, status, code int) {
+	var envelope dto.TemperatureErrorOut
+	envelope.Error.Code = code
+	envelope.Error.Message = weatherErrorMessages[code]
+
+	body, _ := json.Marshal(envelope)
+	writeJSON(writer, status, body)
+}
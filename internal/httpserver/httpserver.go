@@ -0,0 +1,97 @@
+// Package httpserver builds the HTTP servers of both services with timeouts
+// and header limits, and recovers from panics in their handlers.
+package httpserver
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Defaults for the settings left at zero in config.HTTPServer. The write
+// timeout leaves room for service B's two upstream calls of up to 10 seconds
+// each.
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 10 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 2 * time.Minute
+	DefaultMaxHeaderBytes    = 64 << 10
+)
+
+// New returns a server for handler on addr. Without a read header timeout a
+// client can hold a connection open forever by sending its headers slowly.
+func New(addr string, handler http.Handler, cfg config.HTTPServer) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: orDefault(cfg.ReadHeaderTimeout, DefaultReadHeaderTimeout),
+		ReadTimeout:       orDefault(cfg.ReadTimeout, DefaultReadTimeout),
+		WriteTimeout:      orDefault(cfg.WriteTimeout, DefaultWriteTimeout),
+		IdleTimeout:       orDefault(cfg.IdleTimeout, DefaultIdleTimeout),
+		MaxHeaderBytes:    orDefault(cfg.MaxHeaderBytes, DefaultMaxHeaderBytes),
+	}
+}
+
+func orDefault[T time.Duration | int](value, fallback T) T {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+// Recover answers a panicking request with a 500 instead of dropping the
+// connection, logging the stack trace and recording the panic on the span in
+// the request's context, if any. http.ErrAbortHandler is let through, as it
+// is how a handler asks the server to abort the response.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			log.Printf("panic serving %s %s: %v\n%s", request.Method, request.URL.Path, recovered, debug.Stack())
+			recordPanic(trace.SpanFromContext(request.Context()), recovered)
+			http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(writer, request)
+	})
+}
+
+// EndSpan ends span, first recording a panic in progress on it. Handlers
+// defer it instead of span.End so the panic reaches their trace before
+// Recover answers it.
+func EndSpan(span trace.Span, options ...trace.SpanEndOption) {
+	recovered := recover()
+	if recovered != nil {
+		recordPanic(span, recovered)
+	}
+	span.End(options...)
+
+	if recovered != nil {
+		panic(recovered)
+	}
+}
+
+func recordPanic(span trace.Span, recovered any) {
+	if !span.IsRecording() {
+		return
+	}
+
+	err := fmt.Errorf("panic: %v", recovered)
+	span.RecordError(err, trace.WithStackTrace(true))
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,154 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func TestNewAppliesDefaults(t *testing.T) {
+	server := New(":0", http.NotFoundHandler(), config.HTTPServer{WriteTimeout: time.Minute})
+
+	assert.Equal(t, DefaultReadHeaderTimeout, server.ReadHeaderTimeout)
+	assert.Equal(t, DefaultReadTimeout, server.ReadTimeout)
+	assert.Equal(t, time.Minute, server.WriteTimeout)
+	assert.Equal(t, DefaultIdleTimeout, server.IdleTimeout)
+	assert.Equal(t, DefaultMaxHeaderBytes, server.MaxHeaderBytes)
+}
+
+func TestNewClosesSlowHeaders(t *testing.T) {
+	server := New("127.0.0.1:0", http.NotFoundHandler(), config.HTTPServer{ReadHeaderTimeout: 50 * time.Millisecond})
+	listener, listenErr := net.Listen("tcp", server.Addr)
+	require.NoError(t, listenErr)
+	go server.Serve(listener)
+	defer server.Close()
+
+	conn, dialErr := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, dialErr)
+	defer conn.Close()
+
+	_, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, err = io.ReadAll(conn)
+	assert.NoError(t, err, "the server should close the connection before the client's deadline")
+}
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(previous) })
+	return &buf
+}
+
+func TestRecover(t *testing.T) {
+	logs := captureLog(t)
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracer.Start(r.Context(), "handler")
+		defer EndSpan(span)
+
+		var cities map[string]string
+		cities["Gramado"] = "RS"
+	})
+
+	rec := httptest.NewRecorder()
+	Recover(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/temperature/95670084", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, logs.String(), "panic serving GET /temperature/95670084: assignment to entry in nil map")
+	assert.Contains(t, logs.String(), "httpserver.TestRecover")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Len(t, spans[0].Events(), 1)
+	event := spans[0].Events()[0]
+	assert.Equal(t, semconv.ExceptionEventName, event.Name)
+	assert.Contains(t, event.Attributes, semconv.ExceptionMessage("panic: assignment to entry in nil map"))
+}
+
+func TestRecoverRecordsOnTheRequestSpan(t *testing.T) {
+	captureLog(t)
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	ctx, span := tracer.Start(context.Background(), "request")
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") })).ServeHTTP(httptest.NewRecorder(), req)
+	span.End()
+
+	require.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, "panic: boom", recorder.Ended()[0].Status().Description)
+}
+
+func TestRecoverLetsAbortHandlerThrough(t *testing.T) {
+	handler := Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic(http.ErrAbortHandler) }))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestEndSpanWithoutPanic(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	_, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "ok")
+
+	func() {
+		defer EndSpan(span)
+	}()
+
+	require.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, codes.Unset, recorder.Ended()[0].Status().Code)
+	assert.Empty(t, recorder.Ended()[0].Events())
+}
+
+func TestInProcess(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/temperature?cep=95670084", r.RequestURI)
+		assert.Equal(t, "service-b", r.Host)
+		assert.Equal(t, "pt-BR", r.Header.Get("Accept-Language"))
+
+		w.Header().Set("Content-Language", "pt")
+		w.WriteHeader(http.StatusTeapot)
+		w.Header().Set("X-After-Status", "ignored")
+		_, _ = io.WriteString(w, "Gramado")
+	})
+	client := &http.Client{Transport: InProcess(handler)}
+
+	req, reqErr := http.NewRequest(http.MethodGet, "http://service-b/temperature?cep=95670084", nil)
+	require.NoError(t, reqErr)
+	req.Header.Set("Accept-Language", "pt-BR")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, readErr := io.ReadAll(resp.Body)
+	require.NoError(t, readErr)
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+	assert.Equal(t, "418 I'm a teapot", resp.Status)
+	assert.Equal(t, "pt", resp.Header.Get("Content-Language"))
+	assert.Empty(t, resp.Header.Get("X-After-Status"))
+	assert.Equal(t, "Gramado", string(body))
+}
@@ -0,0 +1,73 @@
+package httpserver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// InProcess returns a transport answering every request with handler in the
+// calling goroutine, as if it were served over the network, without opening
+// a connection.
+func InProcess(handler http.Handler) http.RoundTripper {
+	return inProcess{handler: handler}
+}
+
+type inProcess struct {
+	handler http.Handler
+}
+
+func (t inProcess) RoundTrip(req *http.Request) (*http.Response, error) {
+	serverReq := req.Clone(req.Context())
+	serverReq.RequestURI = req.URL.RequestURI()
+	serverReq.RemoteAddr = "in-process"
+	if serverReq.Body == nil {
+		serverReq.Body = http.NoBody
+	}
+	if serverReq.Host == "" {
+		serverReq.Host = req.URL.Host
+	}
+
+	writer := &bufferedWriter{header: http.Header{}}
+	t.handler.ServeHTTP(writer, serverReq)
+	writer.WriteHeader(http.StatusOK)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", writer.status, http.StatusText(writer.status)),
+		StatusCode:    writer.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        writer.written,
+		Body:          io.NopCloser(bytes.NewReader(writer.body.Bytes())),
+		ContentLength: int64(writer.body.Len()),
+		Request:       req,
+	}, nil
+}
+
+// bufferedWriter keeps the response in memory, snapshotting the header the
+// way net/http does when the status is written.
+type bufferedWriter struct {
+	header  http.Header
+	written http.Header
+	status  int
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	w.written = w.header.Clone()
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
@@ -1,5 +0,0 @@
-package dto
-
-type LocationInput struct {
-	CEP string `json:"cep"`
-}
@@ -0,0 +1,44 @@
+package opentel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// NewMeterProvider returns a provider pushing serviceName's metrics to the
+// OTLP/HTTP collectorURL, such as http://otel_collector:4318/v1/metrics.
+// Without a collectorURL the metrics are recorded but never exported. Like
+// NewProvider, it is meant to be injected and only becomes the otel global
+// with WithGlobal.
+func NewMeterProvider(serviceName, collectorURL string, opts ...Option) (*sdkmetric.MeterProvider, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx := context.Background()
+	res, resErr := newResource(ctx, serviceName, o)
+	if resErr != nil {
+		return nil, fmt.Errorf("failed to create reosource %w", resErr)
+	}
+
+	providerOpts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	if collectorURL != "" {
+		exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(collectorURL))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+		}
+		providerOpts = append(providerOpts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+	}
+
+	meterProvider := sdkmetric.NewMeterProvider(providerOpts...)
+	if o.global {
+		otel.SetMeterProvider(meterProvider)
+	}
+
+	return meterProvider, nil
+}
@@ -3,46 +3,77 @@ package opentel
 import (
 	"context"
 	"fmt"
-	"time"
 
+	"github.com/MatheusBenetti/opentelemetry/config"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/zipkin"
 	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 )
 
-type ShutdownFunction func(ctx context.Context) error
+type options struct {
+	global      bool
+	environment string
+	attributes  map[string]string
+}
 
-func InitProvider(serviceName, collectorURL string) (ShutdownFunction, error) {
-	ctx := context.Background()
-	res, resErr := resource.New(
-		ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(serviceName),
-		),
-	)
+// Option customises the provider built by NewProvider.
+type Option func(*options)
+
+// WithGlobal also installs the provider, and Propagator, as the otel globals,
+// for instrumentation that isn't handed a provider.
+func WithGlobal() Option {
+	return func(o *options) {
+		o.global = true
+	}
+}
+
+// WithConfig describes the deployment in the provider's resource, with the
+// configured environment and extra attributes.
+func WithConfig(cfg config.Otel) Option {
+	return func(o *options) {
+		o.environment = cfg.Environment
+		o.attributes = cfg.Attributes
+	}
+}
+
+// NewProvider returns a provider exporting serviceName's spans to Zipkin. It
+// is meant to be injected into the servers and repositories of one service,
+// so several services can trace from one process; the otel globals are left
+// alone unless WithGlobal is given.
+func NewProvider(serviceName, collectorURL string, opts ...Option) (*sdktrace.TracerProvider, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	res, resErr := newResource(context.Background(), serviceName, o)
 	if resErr != nil {
 		return nil, fmt.Errorf("failed to create reosource %w", resErr)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
 	exporter, err := zipkin.New(collectorURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Zipkin exporter: %w", err)
 	}
 
-	bsp := sdktrace.NewBatchSpanProcessor(exporter)
 	traceProvider := sdktrace.NewTracerProvider(
 		sdktrace.WithSampler(sdktrace.AlwaysSample()),
 		sdktrace.WithResource(res),
-		sdktrace.WithSpanProcessor(bsp),
+		sdktrace.WithBatcher(exporter),
 	)
-	otel.SetTracerProvider(traceProvider)
-	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if o.global {
+		otel.SetTracerProvider(traceProvider)
+		otel.SetTextMapPropagator(Propagator())
+	}
+
+	return traceProvider, nil
+}
 
-	return traceProvider.Shutdown, nil
+// Propagator carries the trace context and the baggage between services.
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	)
 }
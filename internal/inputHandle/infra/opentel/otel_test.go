@@ -0,0 +1,127 @@
+package opentel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func restoreGlobals(t *testing.T) {
+	t.Helper()
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+}
+
+func TestNewProviderLeavesGlobalsAlone(t *testing.T) {
+	restoreGlobals(t)
+	before := otel.GetTracerProvider()
+
+	provider, err := NewProvider("service_b", "http://localhost:9411/api/v2/spans")
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	assert.Same(t, before, otel.GetTracerProvider())
+}
+
+func TestNewProviderWithGlobal(t *testing.T) {
+	restoreGlobals(t)
+
+	provider, err := NewProvider("service_b", "http://localhost:9411/api/v2/spans", WithGlobal())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	assert.Same(t, provider, otel.GetTracerProvider())
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+}
+
+func TestNewResource(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=staging,team=weather,service.name=from-attributes")
+	t.Setenv("OTEL_SERVICE_NAME", "from-env")
+	previous := Version
+	Version = "v1.2.3"
+	t.Cleanup(func() { Version = previous })
+
+	res, err := newResource(context.Background(), "service_b", options{
+		environment: "development",
+		attributes:  map[string]string{"service.namespace": "temperature", "team": "platform"},
+	})
+	require.NoError(t, err)
+
+	attrs := res.Set()
+	for _, expected := range []attribute.KeyValue{
+		semconv.ServiceName("service_b"),
+		semconv.ServiceVersion("v1.2.3"),
+		semconv.ServiceInstanceID(instanceID),
+		semconv.ServiceNamespace("temperature"),
+		semconv.DeploymentEnvironment("staging"),
+		attribute.String("team", "weather"),
+		semconv.ProcessPID(os.Getpid()),
+		semconv.OSTypeKey.String(runtime.GOOS),
+		semconv.TelemetrySDKLanguageGo,
+	} {
+		value, ok := attrs.Value(expected.Key)
+		if assert.True(t, ok, "missing %s", expected.Key) {
+			assert.Equal(t, expected.Value, value, "%s", expected.Key)
+		}
+	}
+	for _, key := range []attribute.Key{semconv.HostNameKey, semconv.ProcessExecutableNameKey, semconv.ProcessRuntimeVersionKey} {
+		assert.True(t, attrs.HasValue(key), "missing %s", key)
+	}
+	assert.False(t, attrs.HasValue(semconv.ProcessCommandArgsKey), "the command line may hold secrets")
+	assert.False(t, attrs.HasValue(semconv.ProcessOwnerKey), "the OS user name is not exported")
+}
+
+func TestNewResourceWithoutOverrides(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+
+	res, err := newResource(context.Background(), "service_a_orchestration", options{environment: "production"})
+	require.NoError(t, err)
+
+	env, _ := res.Set().Value(semconv.DeploymentEnvironmentKey)
+	assert.Equal(t, "production", env.AsString())
+	name, _ := res.Set().Value(semconv.ServiceNameKey)
+	assert.Equal(t, "service_a_orchestration", name.AsString())
+}
+
+func TestNewInstanceID(t *testing.T) {
+	id := newInstanceID()
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+	assert.NotEqual(t, id, newInstanceID())
+}
+
+func TestNewMeterProviderExportsToCollector(t *testing.T) {
+	received := make(chan string, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- r.URL.Path:
+		default:
+		}
+	}))
+	defer collector.Close()
+
+	before := otel.GetMeterProvider()
+	provider, err := NewMeterProvider("service_a_orchestration", collector.URL+"/v1/metrics")
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+	assert.Same(t, before, otel.GetMeterProvider())
+
+	counter, counterErr := provider.Meter("test").Int64Counter("test.requests")
+	require.NoError(t, counterErr)
+	counter.Add(context.Background(), 1)
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	assert.Equal(t, "/v1/metrics", <-received)
+}
@@ -0,0 +1,135 @@
+package opentel
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// Version is the service.version of every provider. Release builds set it
+// with
+//
+//	-ldflags "-X github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/opentel.Version=v1.2.3"
+//
+// and other builds fall back to the module version or VCS revision stamped
+// by the go command.
+var Version string
+
+// instanceID tells apart processes running the same service. It is shared by
+// every provider in the process, so both services of the all-in-one mode
+// report the same instance.
+var instanceID = newInstanceID()
+
+// newResource describes serviceName's process. Later sources win over earlier
+// ones: the detected host, OS, container and process, then the configured
+// attributes and environment, then OTEL_RESOURCE_ATTRIBUTES and finally the
+// service identity. The identity comes last so OTEL_SERVICE_NAME can't give
+// both services of the all-in-one mode the same name.
+func newResource(ctx context.Context, serviceName string, o options) (*resource.Resource, error) {
+	identity := []attribute.KeyValue{
+		semconv.ServiceName(serviceName),
+		semconv.ServiceInstanceID(instanceID),
+	}
+	if version := serviceVersion(); version != "" {
+		identity = append(identity, semconv.ServiceVersion(version))
+	}
+
+	configured := configuredAttributes(o.attributes)
+	if o.environment != "" {
+		configured = append(configured, semconv.DeploymentEnvironment(o.environment))
+	}
+
+	res, err := resource.New(
+		ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithOS(),
+		resource.WithContainer(),
+		// The command line is left out, as it may carry secrets such as
+		// tempctl's -api-key, and so is the owner, the OS user name.
+		resource.WithProcessPID(),
+		resource.WithProcessExecutableName(),
+		resource.WithProcessExecutablePath(),
+		resource.WithProcessRuntimeName(),
+		resource.WithProcessRuntimeVersion(),
+		resource.WithProcessRuntimeDescription(),
+		resource.WithAttributes(configured...),
+		resource.WithFromEnv(),
+		resource.WithAttributes(identity...),
+	)
+	if errors.Is(err, resource.ErrPartialResource) {
+		log.Printf("some resource attributes of %s could not be detected %s\n", serviceName, err.Error())
+		return res, nil
+	}
+
+	return res, err
+}
+
+func configuredAttributes(attributes map[string]string) []attribute.KeyValue {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]attribute.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, attribute.String(key, attributes[key]))
+	}
+
+	return kvs
+}
+
+// serviceVersion prefers Version, then the module version of binaries built
+// with go install, then the VCS revision, marked when the tree was dirty.
+func serviceVersion() string {
+	if Version != "" {
+		return Version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	var revision, modified string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value
+		}
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if revision != "" && modified == "true" {
+		revision += "-dirty"
+	}
+
+	return revision
+}
+
+// newInstanceID returns a random UUID v4.
+func newInstanceID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
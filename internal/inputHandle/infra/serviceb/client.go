@@ -0,0 +1,21 @@
+package serviceb
+
+import (
+	"context"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+)
+
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// Client fetches the temperature for a CEP from service B. Implementations
+// return the entity errors service B answered with, so callers don't need to
+// know which transport is in use. *contract.Client is the HTTP one.
+type Client interface {
+	Temperature(ctx context.Context, req contract.TemperatureRequest) (contract.TemperatureResponse, error)
+}
+
+var _ Client = (*contract.Client)(nil)
@@ -0,0 +1,192 @@
+package serviceb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/grpcapi"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/grpcapi/pb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type GRPCClient struct {
+	conn    *grpc.ClientConn
+	client  pb.TemperatureServiceClient
+	timeout time.Duration
+}
+
+// defaultGRPCTimeout bounds a GetWeather call, or the wait for each result of
+// a StreamWeather call, unless WithTimeout says otherwise.
+const defaultGRPCTimeout = 10 * time.Second
+
+type grpcOptions struct {
+	otel    []otelgrpc.Option
+	timeout time.Duration
+}
+
+// GRPCOption customises the connection opened by NewGRPCClient.
+type GRPCOption func(*grpcOptions)
+
+// WithTracerProvider records the client spans with provider instead of the
+// global one.
+func WithTracerProvider(provider trace.TracerProvider) GRPCOption {
+	return func(o *grpcOptions) {
+		o.otel = append(o.otel, otelgrpc.WithTracerProvider(provider))
+	}
+}
+
+// WithTimeout bounds each Temperature call and, in TemperatureBatch, the wait
+// for each result, so a large batch isn't cut short while every lookup is
+// fast. Zero keeps the default of 10 seconds.
+func WithTimeout(timeout time.Duration) GRPCOption {
+	return func(o *grpcOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+func NewGRPCClient(target string, opts ...GRPCOption) (*GRPCClient, error) {
+	o := grpcOptions{timeout: defaultGRPCTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	conn, dialErr := grpc.Dial(
+		target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(o.otel...)),
+	)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+
+	return &GRPCClient{
+		conn:    conn,
+		client:  pb.NewTemperatureServiceClient(conn),
+		timeout: o.timeout,
+	}, nil
+}
+
+func (gc *GRPCClient) Temperature(ctx context.Context, req contract.TemperatureRequest) (contract.TemperatureResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, gc.timeout)
+	defer cancel()
+
+	resp, callErr := gc.client.GetWeather(ctx, &pb.GetWeatherRequest{
+		Cep:      req.CEP,
+		Units:    req.Units,
+		Language: req.Language,
+	})
+	if callErr != nil {
+		return contract.TemperatureResponse{}, errorFromStatus(callErr)
+	}
+
+	return fromResponse(resp), nil
+}
+
+// BatchResult is one entry of a TemperatureBatch lookup.
+type BatchResult struct {
+	CEP         string
+	Temperature contract.TemperatureResponse
+	Err         error
+}
+
+// TemperatureBatch streams the temperature of every CEP from service B over a
+// single call, invoking fn as each result arrives. The call is cancelled when
+// a result takes longer than the client's timeout to arrive.
+func (gc *GRPCClient) TemperatureBatch(ctx context.Context, ceps []string, units, lang string, fn func(BatchResult)) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	idle := time.AfterFunc(gc.timeout, func() {
+		cancel(fmt.Errorf("waited %s for the next batch result: %w", gc.timeout, context.DeadlineExceeded))
+	})
+	defer idle.Stop()
+
+	stream, callErr := gc.client.StreamWeather(ctx, &pb.StreamWeatherRequest{
+		Ceps:     ceps,
+		Units:    units,
+		Language: lang,
+	})
+	if callErr != nil {
+		return errorFromStatus(callErr)
+	}
+
+	for {
+		resp, recvErr := stream.Recv()
+		if recvErr != nil {
+			if errors.Is(recvErr, io.EOF) {
+				return nil
+			}
+			if cause := context.Cause(ctx); errors.Is(cause, context.DeadlineExceeded) {
+				return cause
+			}
+			return errorFromStatus(recvErr)
+		}
+		idle.Reset(gc.timeout)
+
+		result := BatchResult{CEP: resp.GetCep()}
+		if resErr := resp.GetError(); resErr != nil {
+			code := codes.Code(resErr.GetCode())
+			result.Err = knownError(code, resErr.GetReason(), status.Error(code, resErr.GetMessage()))
+		} else {
+			result.Temperature = fromResponse(resp.GetWeather())
+		}
+		fn(result)
+	}
+}
+
+func (gc *GRPCClient) Close() error {
+	return gc.conn.Close()
+}
+
+func fromResponse(resp *pb.GetWeatherResponse) contract.TemperatureResponse {
+	out := contract.TemperatureResponse{
+		Location:  resp.GetLocation(),
+		Condition: resp.GetCondition(),
+		TempC:     resp.TempC,
+		TempF:     resp.TempF,
+		TempK:     resp.TempK,
+		TempR:     resp.TempR,
+		TempRe:    resp.TempRe,
+	}
+	if resp.LastUpdated != nil {
+		lastUpdated := resp.GetLastUpdated().AsTime()
+		out.LastUpdated = &lastUpdated
+	}
+
+	return out
+}
+
+// errorFromStatus recovers the entity error named by the ErrorInfo detail of
+// err's status.
+func errorFromStatus(err error) error {
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == grpcapi.ErrorDomain {
+			return knownError(st.Code(), info.GetReason(), err)
+		}
+	}
+
+	return err
+}
+
+// knownError returns the entity error named by reason, as long as code is the
+// one service B answers it with, or fallback.
+func knownError(code codes.Code, reason string, fallback error) error {
+	if known := contract.ErrorForCode(reason); known != nil && grpcapi.CodeFor(known) == code {
+		return known
+	}
+
+	return fallback
+}
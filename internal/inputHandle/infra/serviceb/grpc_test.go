@@ -0,0 +1,179 @@
+package serviceb
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/grpcapi"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase/usecasetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func startGRPC(t *testing.T) *GRPCClient {
+	t.Helper()
+
+	gw := usecase.NewGetWeather(
+		usecasetest.Locations{"95670084": "Gramado", "99999999": "Quota"},
+		usecasetest.Temperatures{"Quota": fmt.Errorf("%w: code 2007", entity.ErrWeatherQuotaExceeded)},
+		nil,
+	)
+
+	return dialGRPC(t, gw)
+}
+
+func dialGRPC(t *testing.T, gw usecase.GetWeather, opts ...GRPCOption) *GRPCClient {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcapi.NewServer(gw, noop.NewTracerProvider(), "service_b:all").Serve(listener)
+	t.Cleanup(func() { listener.Close() })
+
+	client, err := NewGRPCClient(listener.Addr().String(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+// slowLocations takes delay to answer each lookup.
+type slowLocations struct {
+	usecasetest.Locations
+	delay time.Duration
+}
+
+func (s slowLocations) Get(ctx context.Context, cep string) (entity.Location, error) {
+	time.Sleep(s.delay)
+	return s.Locations.Get(ctx, cep)
+}
+
+// panickingLocations panics looking up its CEPs.
+type panickingLocations struct {
+	usecasetest.Locations
+	panics map[string]bool
+}
+
+func (p panickingLocations) Get(ctx context.Context, cep string) (entity.Location, error) {
+	if p.panics[cep] {
+		panic("lookup of " + cep)
+	}
+	return p.Locations.Get(ctx, cep)
+}
+
+func TestGRPCClientTemperature(t *testing.T) {
+	client := startGRPC(t)
+
+	out, err := client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "95670084"})
+	require.NoError(t, err)
+	assert.Equal(t, "Gramado", out.Location)
+	assert.Equal(t, "Sunny", out.Condition)
+	require.NotNil(t, out.TempC)
+	assert.Equal(t, 25.0, *out.TempC)
+	assert.Nil(t, out.TempR)
+	require.NotNil(t, out.LastUpdated)
+	assert.Equal(t, usecasetest.UpdatedAt, *out.LastUpdated)
+
+	out, err = client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "95670084", Units: "R", Language: "pt-BR"})
+	require.NoError(t, err)
+	assert.Equal(t, "Ensolarado", out.Condition)
+	assert.Nil(t, out.TempC)
+	require.NotNil(t, out.TempR)
+	assert.Equal(t, 536.67, *out.TempR)
+
+	_, err = client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "123"})
+	assert.ErrorIs(t, err, entity.ErrCEPNotValid)
+
+	_, err = client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "95670084", Units: "X"})
+	assert.ErrorIs(t, err, entity.ErrUnitNotValid)
+
+	_, err = client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "01001000"})
+	assert.ErrorIs(t, err, entity.ErrCEPNotFound)
+
+	_, err = client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "99999999"})
+	assert.ErrorIs(t, err, entity.ErrWeatherQuotaExceeded)
+}
+
+func TestGRPCClientTemperatureBatch(t *testing.T) {
+	client := startGRPC(t)
+
+	var results []BatchResult
+	err := client.TemperatureBatch(context.Background(), []string{"95670084", "01001000", "abc"}, "", "", func(r BatchResult) {
+		results = append(results, r)
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	sort.Slice(results, func(i, j int) bool { return results[i].CEP < results[j].CEP })
+
+	assert.ErrorIs(t, results[0].Err, entity.ErrCEPNotFound)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "Gramado", results[1].Temperature.Location)
+	assert.ErrorIs(t, results[2].Err, entity.ErrCEPNotValid)
+}
+
+func TestGRPCClientTemperatureBatchTimesOutPerResult(t *testing.T) {
+	gw := usecase.NewGetWeather(
+		slowLocations{Locations: usecasetest.Locations{"95670084": "Gramado"}, delay: 30 * time.Millisecond},
+		usecasetest.Temperatures{},
+		nil,
+	)
+	ceps := []string{"95670084", "95670084", "95670084", "95670084", "95670084", "95670084"}
+
+	var results []BatchResult
+	err := dialGRPC(t, gw, WithTimeout(150*time.Millisecond)).TemperatureBatch(context.Background(), ceps, "", "", func(r BatchResult) {
+		results = append(results, r)
+	})
+	require.NoError(t, err, "the timeout bounds each result, not the whole batch")
+	assert.Len(t, results, len(ceps))
+
+	err = dialGRPC(t, gw, WithTimeout(5*time.Millisecond)).TemperatureBatch(context.Background(), ceps, "", "", func(BatchResult) {})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGRPCServerRecoversFromPanics(t *testing.T) {
+	gw := usecase.NewGetWeather(
+		panickingLocations{usecasetest.Locations{"95670084": "Gramado"}, map[string]bool{"01001000": true}},
+		usecasetest.Temperatures{},
+		nil,
+	)
+	client := dialGRPC(t, gw)
+
+	_, err := client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "01001000"})
+	require.Error(t, err)
+	assert.Equal(t, codes.Internal, status.Code(err), err.Error())
+
+	err = client.TemperatureBatch(context.Background(), []string{"95670084", "01001000"}, "", "", func(BatchResult) {})
+	require.Error(t, err)
+
+	// The server is still up.
+	out, err := client.Temperature(context.Background(), contract.TemperatureRequest{CEP: "95670084"})
+	require.NoError(t, err)
+	assert.Equal(t, "Gramado", out.Location)
+}
+
+func TestErrorFromStatus(t *testing.T) {
+	withInfo := func(code codes.Code, reason, message string) error {
+		st, err := status.New(code, message).WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: grpcapi.ErrorDomain})
+		require.NoError(t, err)
+		return st.Err()
+	}
+
+	assert.ErrorIs(t, errorFromStatus(withInfo(codes.NotFound, contract.CodeCEPNotFound, "anything")), entity.ErrCEPNotFound)
+
+	mismatched := withInfo(codes.Internal, contract.CodeCEPNotFound, "can not found zipcode")
+	assert.NotErrorIs(t, errorFromStatus(mismatched), entity.ErrCEPNotFound, "the status code must agree with the reason")
+
+	messageOnly := status.Error(codes.NotFound, entity.ErrCEPNotFound.Error())
+	assert.NotErrorIs(t, errorFromStatus(messageOnly), entity.ErrCEPNotFound, "messages are not matched")
+}
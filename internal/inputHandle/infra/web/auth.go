@@ -0,0 +1,137 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"go.opentelemetry.io/otel/baggage"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultAuthHeader = "X-API-Key"
+
+	// ClientIDBaggageKey carries the authenticated client to downstream services.
+	ClientIDBaggageKey = contract.ClientIDBaggageKey
+)
+
+type clientIDKey struct{}
+
+type hashedKey struct {
+	id   string
+	hash []byte
+}
+
+// Authenticator checks the API key sent by the client against the SHA-256
+// hashes in the config and stores the matching key ID in the request context.
+type Authenticator struct {
+	enabled bool
+	header  string
+	keys    []hashedKey
+}
+
+func NewAuthenticator(cfg config.Auth) *Authenticator {
+	header := cfg.Header
+	if header == "" {
+		header = defaultAuthHeader
+	}
+
+	keys := make([]hashedKey, 0, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		hash, decErr := hex.DecodeString(strings.TrimSpace(key.Hash))
+		if decErr != nil || len(hash) != sha256.Size {
+			continue
+		}
+		keys = append(keys, hashedKey{id: key.ID, hash: hash})
+	}
+
+	return &Authenticator{
+		enabled: cfg.Enabled,
+		header:  header,
+		keys:    keys,
+	}
+}
+
+func (au *Authenticator) identify(key string) (string, bool) {
+	sum := sha256.Sum256([]byte(key))
+
+	id, found := "", false
+	for _, k := range au.keys {
+		if subtle.ConstantTimeCompare(sum[:], k.hash) == 1 {
+			id, found = k.id, true
+		}
+	}
+
+	return id, found
+}
+
+// verify returns the ID of the key sent with request, if it is a known one.
+func (au *Authenticator) verify(request *http.Request) (string, bool) {
+	key := request.Header.Get(au.header)
+	if key == "" {
+		return "", false
+	}
+
+	return au.identify(key)
+}
+
+func (au *Authenticator) Middleware(next http.Handler) http.Handler {
+	if !au.enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		key := request.Header.Get(au.header)
+		if key == "" {
+			writer.Header().Set("WWW-Authenticate", "ApiKey header=\""+au.header+"\"")
+			http.Error(writer, "missing API key", http.StatusUnauthorized)
+			return
+		}
+
+		id, ok := au.identify(key)
+		if !ok {
+			writer.Header().Set("WWW-Authenticate", "ApiKey header=\""+au.header+"\"")
+			http.Error(writer, "invalid API key", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(request.Context(), clientIDKey{}, id)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// ClientID returns the authenticated client stored by the Authenticator.
+func ClientID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(clientIDKey{}).(string)
+	return id, ok
+}
+
+// withClientIdentity records the authenticated client on the span and puts it
+// in the baggage, replacing whatever value the caller may have sent.
+func withClientIdentity(ctx context.Context, span trace.Span) context.Context {
+	bag := baggage.FromContext(ctx).DeleteMember(ClientIDBaggageKey)
+
+	id, ok := ClientID(ctx)
+	if !ok {
+		return baggage.ContextWithBaggage(ctx, bag)
+	}
+	span.SetAttributes(semconv.EnduserID(id))
+
+	member, memErr := baggage.NewMemberRaw(ClientIDBaggageKey, id)
+	if memErr != nil {
+		return baggage.ContextWithBaggage(ctx, bag)
+	}
+	withID, bagErr := bag.SetMember(member)
+	if bagErr != nil {
+		return baggage.ContextWithBaggage(ctx, bag)
+	}
+
+	return baggage.ContextWithBaggage(ctx, withID)
+}
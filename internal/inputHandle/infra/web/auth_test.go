@@ -0,0 +1,63 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	sum := sha256.Sum256([]byte("secret-key"))
+	auth := NewAuthenticator(config.Auth{
+		Enabled: true,
+		Keys:    []config.APIKey{{ID: "team-a", Hash: hex.EncodeToString(sum[:])}},
+	})
+
+	var gotID string
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID, _ = ClientID(r.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		key      string
+		expected int
+	}{
+		{"missing key", "", http.StatusUnauthorized},
+		{"invalid key", "wrong", http.StatusUnauthorized},
+		{"valid key", "secret-key", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/getCep", nil)
+			if tt.key != "" {
+				req.Header.Set(defaultAuthHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+	assert.Equal(t, "team-a", gotID)
+}
+
+func TestWithClientIdentityOverridesBaggage(t *testing.T) {
+	spoofed, _ := baggage.NewMemberRaw(ClientIDBaggageKey, "spoofed")
+	bag, _ := baggage.New(spoofed)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+	span := noop.Span{}
+
+	assert.Empty(t, baggage.FromContext(withClientIdentity(ctx, span)).Member(ClientIDBaggageKey).Value())
+
+	ctx = context.WithValue(ctx, clientIDKey{}, "team-a")
+	assert.Equal(t, "team-a", baggage.FromContext(withClientIdentity(ctx, span)).Member(ClientIDBaggageKey).Value())
+}
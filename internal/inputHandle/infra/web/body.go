@@ -0,0 +1,171 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes caps request bodies when Server.MaxBodyBytes is unset.
+const DefaultMaxBodyBytes = 1 << 20
+
+// requestError is a client error answered with its own status instead of a
+// 400.
+type requestError struct {
+	status  int
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// writeRequestError answers err with a 400, or the status of a requestError.
+func writeRequestError(writer http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		status = reqErr.status
+	}
+	http.Error(writer, err.Error(), status)
+}
+
+// limitBody answers requests whose body isn't JSON with a 415 and those over
+// maxBytes with a 413. Accepted bodies are buffered, so the OpenAPI
+// validation and the handler both read them without a second limit.
+func limitBody(maxBytes int64) Middleware {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.ContentLength == 0 {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			if err := requireJSON(request.Header.Get("Content-Type")); err != nil {
+				http.Error(writer, err.Error(), http.StatusUnsupportedMediaType)
+				return
+			}
+
+			tooLarge := fmt.Sprintf("request body exceeds %d bytes", maxBytes)
+			if request.ContentLength > maxBytes {
+				http.Error(writer, tooLarge, http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			body, readErr := io.ReadAll(http.MaxBytesReader(writer, request.Body, maxBytes))
+			if readErr != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(readErr, &maxBytesErr) {
+					http.Error(writer, tooLarge, http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(writer, "failed reading the request body: "+readErr.Error(), http.StatusBadRequest)
+				return
+			}
+
+			request.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// checkBody runs source on the requests to method and path before the rest of
+// the chain, answering its errors the way the handler would. It sits in front
+// of the OpenAPI validation, so a body source's field-level messages reach
+// the client instead of kin-openapi's. The body, buffered by limitBody, is
+// put back for the handler.
+func checkBody(method, path string, source cepSource) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Method != method || request.URL.Path != path {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			body, readErr := io.ReadAll(request.Body)
+			if readErr != nil {
+				writeRequestError(writer, decodeError(readErr))
+				return
+			}
+
+			request.Body = io.NopCloser(bytes.NewReader(body))
+			if _, err := source(request); err != nil {
+				writeRequestError(writer, err)
+				return
+			}
+
+			request.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// requireJSON accepts application/json, optionally with a UTF-8 charset.
+func requireJSON(contentType string) error {
+	if contentType == "" {
+		return errors.New("Content-Type must be application/json")
+	}
+
+	mediaType, params, parseErr := mime.ParseMediaType(contentType)
+	if parseErr != nil || mediaType != "application/json" {
+		return fmt.Errorf("Content-Type must be application/json, got %q", contentType)
+	}
+	if charset, ok := params["charset"]; ok && !strings.EqualFold(charset, "utf-8") {
+		return fmt.Errorf("charset must be utf-8, got %q", charset)
+	}
+
+	return nil
+}
+
+// decodeJSON decodes a single JSON value from body into v, rejecting unknown
+// fields and trailing data, and explains what was wrong in the error.
+func decodeJSON(body io.Reader, v any) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return &requestError{http.StatusBadRequest, "request body must contain a single JSON object"}
+	}
+
+	return nil
+}
+
+func decodeError(err error) error {
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		maxBytesErr *http.MaxBytesError
+	)
+	message := err.Error()
+
+	switch {
+	case errors.Is(err, io.EOF):
+		message = "request body is empty"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		message = "request body is truncated JSON"
+	case errors.As(err, &syntaxErr):
+		message = fmt.Sprintf("request body is malformed JSON at byte %d: %s", syntaxErr.Offset, syntaxErr.Error())
+	case errors.As(err, &typeErr) && typeErr.Field == "":
+		message = fmt.Sprintf("request body must be a JSON object, got %s", typeErr.Value)
+	case errors.As(err, &typeErr):
+		message = fmt.Sprintf("field %q must be a %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	case errors.As(err, &maxBytesErr):
+		return &requestError{http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit)}
+	case strings.HasPrefix(message, "json: unknown field "):
+		message = "unknown field " + strings.TrimPrefix(message, "json: unknown field ")
+	}
+
+	return &requestError{http.StatusBadRequest, message}
+}
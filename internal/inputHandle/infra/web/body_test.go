@@ -0,0 +1,114 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type stubClient struct{}
+
+func (stubClient) Temperature(_ context.Context, req contract.TemperatureRequest) (contract.TemperatureResponse, error) {
+	return contract.TemperatureResponse{Location: "Gramado"}, nil
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"empty", "", "request body is empty"},
+		{"truncated", `{"cep": "9567`, "request body is truncated JSON"},
+		{"malformed", `{"cep" "95670084"}`, "request body is malformed JSON at byte 8: invalid character '\"' after object key"},
+		{"wrong type", `{"cep": 95670084}`, `field "cep" must be a string, got number`},
+		{"not an object", `["95670084"]`, "request body must be a JSON object, got array"},
+		{"unknown field", `{"cep": "95670084", "units": "C"}`, `unknown field "units"`},
+		{"trailing data", `{"cep": "95670084"} {}`, "request body must contain a single JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req contract.CEPRequest
+			err := decodeJSON(strings.NewReader(tt.body), &req)
+			assert.EqualError(t, err, tt.message)
+		})
+	}
+
+	var req contract.CEPRequest
+	require.NoError(t, decodeJSON(strings.NewReader(` {"cep": "95670084"}`+"\n"), &req))
+	assert.Equal(t, "95670084", req.CEP)
+}
+
+func TestRequireJSON(t *testing.T) {
+	assert.NoError(t, requireJSON("application/json"))
+	assert.NoError(t, requireJSON("application/json; charset=UTF-8"))
+	assert.EqualError(t, requireJSON(""), "Content-Type must be application/json")
+	assert.EqualError(t, requireJSON("text/plain"), `Content-Type must be application/json, got "text/plain"`)
+	assert.EqualError(t, requireJSON("application/json; charset=latin1"), `charset must be utf-8, got "latin1"`)
+}
+
+func TestServerRejectsInvalidBodies(t *testing.T) {
+	spec, specErr := openapi.ServiceA()
+	require.NoError(t, specErr)
+
+	for _, withSpec := range []bool{false, true} {
+		server := Server{
+			TemplateData:      TemplateData{OTELTracer: noop.NewTracerProvider().Tracer("")},
+			TemperatureClient: stubClient{},
+			MaxBodyBytes:      64,
+		}
+		if withSpec {
+			server.OpenAPI = spec
+		}
+		handler := server.Handler()
+
+		tests := []struct {
+			name        string
+			body        io.Reader
+			contentType string
+			status      int
+			message     string
+		}{
+			{"valid", strings.NewReader(`{"cep": "95670084"}`), "application/json", http.StatusOK, `"city":"Gramado"`},
+			{"text body", strings.NewReader(`95670084`), "text/plain", http.StatusUnsupportedMediaType, `Content-Type must be application/json, got "text/plain"`},
+			{"no content type", strings.NewReader(`{"cep": "95670084"}`), "", http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+			{"too large", strings.NewReader(`{"cep": "95670084", "padding": "` + strings.Repeat("x", 64) + `"}`), "application/json", http.StatusRequestEntityTooLarge, "request body exceeds 64 bytes"},
+			{"too large without length", io.MultiReader(strings.NewReader(`{"cep": "`), strings.NewReader(strings.Repeat("9", 100)+`"}`)), "application/json", http.StatusRequestEntityTooLarge, "request body exceeds 64 bytes"},
+			{"empty", strings.NewReader(""), "application/json", http.StatusBadRequest, "request body is empty"},
+			{"malformed", strings.NewReader(`{"cep" "95670084"}`), "application/json", http.StatusBadRequest, "request body is malformed JSON at byte 8"},
+			{"unknown field", strings.NewReader(`{"cep": "95670084", "city": "Gramado"}`), "application/json", http.StatusBadRequest, `unknown field "city"`},
+			{"wrong type", strings.NewReader(`{"cep": 95670084}`), "application/json", http.StatusBadRequest, `field "cep" must be a string, got number`},
+		}
+
+		for _, tt := range tests {
+			name := tt.name
+			if withSpec {
+				name += " with OpenAPI"
+			}
+			t.Run(name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodPost, contract.GetCepPath, tt.body)
+				if _, ok := tt.body.(*strings.Reader); !ok {
+					req.ContentLength = -1
+				}
+				if tt.contentType != "" {
+					req.Header.Set("Content-Type", tt.contentType)
+				}
+
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+				assert.Contains(t, rec.Body.String(), tt.message)
+			})
+		}
+	}
+}
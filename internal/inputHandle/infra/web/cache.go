@@ -0,0 +1,66 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// weatherRefreshInterval is how often weatherapi.com refreshes a location's
+// current weather, so an answer stays fresh until its last_updated plus this.
+const weatherRefreshInterval = 15 * time.Minute
+
+// setCacheHeaders sets the caching headers of a GET answer with the given
+// body and reports whether the request's preconditions show the client
+// already has it, in which case the caller answers 304 Not Modified.
+//
+// The ETag covers the body and its language, and Last-Modified and the
+// freshness lifetime come from the upstream's last_updated. Without it the
+// answer must be revalidated every time. Answers to authenticated clients are
+// private, so shared caches don't hand them to clients without a key.
+func setCacheHeaders(header http.Header, request *http.Request, body []byte, lastUpdated *time.Time, private bool, now time.Time) bool {
+	sum := sha256.Sum256(append([]byte(header.Get("Content-Language")+"\n"), body...))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header.Set("ETag", etag)
+	header.Add("Vary", "Accept-Language")
+	if lastUpdated == nil {
+		header.Set("Cache-Control", "no-cache")
+	} else {
+		header.Set("Last-Modified", lastUpdated.UTC().Format(http.TimeFormat))
+		maxAge := lastUpdated.Add(weatherRefreshInterval).Sub(now)
+		maxAge = min(max(maxAge, 0), weatherRefreshInterval)
+		visibility := "public"
+		if private {
+			visibility = "private"
+		}
+		header.Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(maxAge.Seconds())))
+	}
+
+	// If-Modified-Since is only considered without If-None-Match, as in
+	// RFC 9110, section 13.2.2.
+	if ifNoneMatch := request.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	if ifModifiedSince := request.Header.Get("If-Modified-Since"); ifModifiedSince != "" && lastUpdated != nil {
+		since, parseErr := http.ParseTime(ifModifiedSince)
+		return parseErr == nil && !lastUpdated.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
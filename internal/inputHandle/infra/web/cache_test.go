@@ -0,0 +1,105 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestSetCacheHeaders(t *testing.T) {
+	now := time.Date(2024, 3, 10, 14, 0, 0, 0, time.UTC)
+	body := []byte(`{"city":"Gramado"}`)
+
+	tests := []struct {
+		name         string
+		lastUpdated  time.Time
+		cacheControl string
+	}{
+		{"fresh", now.Add(-5 * time.Minute), "public, max-age=600"},
+		{"due for a refresh", now.Add(-time.Hour), "public, max-age=0"},
+		{"clock skew", now.Add(time.Hour), "public, max-age=900"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			notModified := setCacheHeaders(header, httptest.NewRequest(http.MethodGet, "/temperature/95670084", nil), body, &tt.lastUpdated, false, now)
+
+			assert.False(t, notModified)
+			assert.Equal(t, tt.cacheControl, header.Get("Cache-Control"))
+			assert.Equal(t, tt.lastUpdated.Format(http.TimeFormat), header.Get("Last-Modified"))
+		})
+	}
+
+	header := http.Header{}
+	setCacheHeaders(header, httptest.NewRequest(http.MethodGet, "/temperature/95670084", nil), body, nil, false, now)
+	assert.Equal(t, "no-cache", header.Get("Cache-Control"))
+	assert.Empty(t, header.Get("Last-Modified"))
+}
+
+func TestSetCacheHeadersPrivate(t *testing.T) {
+	now := time.Date(2024, 3, 10, 14, 0, 0, 0, time.UTC)
+	lastUpdated := now.Add(-5 * time.Minute)
+
+	header := http.Header{}
+	setCacheHeaders(header, httptest.NewRequest(http.MethodGet, "/temperature/95670084", nil), []byte(`{}`), &lastUpdated, true, now)
+	assert.Equal(t, "private, max-age=600", header.Get("Cache-Control"))
+}
+
+type updatedClient struct{}
+
+func (updatedClient) Temperature(_ context.Context, req contract.TemperatureRequest) (contract.TemperatureResponse, error) {
+	lastUpdated := time.Now().Add(-time.Minute)
+	return contract.TemperatureResponse{Location: "Gramado", LastUpdated: &lastUpdated}, nil
+}
+
+func TestServerCacheControlWithAuth(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		auth := newTestAuthenticator(map[string]string{"team-a": "key-a"})
+		auth.enabled = enabled
+		server := Server{
+			TemplateData:      TemplateData{OTELTracer: noop.NewTracerProvider().Tracer("")},
+			TemperatureClient: updatedClient{},
+			Middlewares:       []Middleware{auth.Middleware},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/temperature/95670084", nil)
+		req.Header.Set("X-API-Key", "key-a")
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		expected := "public, "
+		if enabled {
+			expected = "private, "
+		}
+		assert.True(t, strings.HasPrefix(rec.Header().Get("Cache-Control"), expected), "auth enabled %t: %s", enabled, rec.Header().Get("Cache-Control"))
+	}
+}
+
+func TestSetCacheHeadersETagCoversLanguage(t *testing.T) {
+	body := []byte(`{"city":"Gramado"}`)
+	etagFor := func(lang string) string {
+		header := http.Header{"Content-Language": {lang}}
+		setCacheHeaders(header, httptest.NewRequest(http.MethodGet, "/", nil), body, nil, false, time.Now())
+		return header.Get("ETag")
+	}
+
+	assert.Equal(t, etagFor("en"), etagFor("en"))
+	assert.NotEqual(t, etagFor("en"), etagFor("pt"))
+}
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`"x", W/"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`*`, `"abc"`))
+	assert.False(t, etagMatches(`"abcd"`, `"abc"`))
+}
@@ -0,0 +1,224 @@
+package web
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	scopeGlobal = "global"
+	scopeClient = "client"
+
+	clientIdleTimeout = 10 * time.Minute
+)
+
+type tokenBucket struct {
+	rate     float64
+	burst    float64
+	tokens   float64
+	last     time.Time
+	lastSeen time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     now,
+		lastSeen: now,
+	}
+}
+
+// take consumes one token, returning how long the caller has to wait for the
+// next one when the bucket is empty.
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// refund gives back a token taken for a request that was rejected anyway.
+func (b *tokenBucket) refund() {
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// RateLimiter applies a global token bucket and one bucket per client, the
+// client being identified by the API key verified by the Authenticator or,
+// without a valid key, the remote IP.
+type RateLimiter struct {
+	cfg       config.RateLimit
+	tracer    trace.Tracer
+	auth      *Authenticator
+	provider  metric.MeterProvider
+	throttled metric.Int64Counter
+	now       func() time.Time
+
+	mu        sync.Mutex
+	global    *tokenBucket
+	clients   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// RateLimitOption customises a RateLimiter.
+type RateLimitOption func(*RateLimiter)
+
+// WithAuthenticator gives each key known to au its own bucket. Without it, or
+// for keys au doesn't know, clients are told apart by their IP, so made-up
+// keys can't be used to get fresh buckets.
+func WithAuthenticator(au *Authenticator) RateLimitOption {
+	return func(rl *RateLimiter) {
+		rl.auth = au
+	}
+}
+
+// WithMeterProvider records service_a.requests.throttled through provider
+// instead of the global one.
+func WithMeterProvider(provider metric.MeterProvider) RateLimitOption {
+	return func(rl *RateLimiter) {
+		rl.provider = provider
+	}
+}
+
+func NewRateLimiter(cfg config.RateLimit, tracer trace.Tracer, opts ...RateLimitOption) *RateLimiter {
+	rl := &RateLimiter{
+		cfg:     cfg,
+		tracer:  tracer,
+		now:     time.Now,
+		clients: make(map[string]*tokenBucket),
+	}
+	for _, opt := range opts {
+		opt(rl)
+	}
+	if rl.provider == nil {
+		rl.provider = otel.GetMeterProvider()
+	}
+
+	rl.throttled, _ = rl.provider.Meter("service_a").Int64Counter(
+		"service_a.requests.throttled",
+		metric.WithDescription("Requests rejected by the rate limiter"),
+	)
+
+	return rl
+}
+
+// allow checks the client's bucket before the global one, so a client over
+// its quota doesn't spend the tokens left for everyone else.
+func (rl *RateLimiter) allow(client string) (bool, string, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	var bucket *tokenBucket
+	if rl.cfg.ClientRPS > 0 {
+		rl.sweep(now)
+		var found bool
+		bucket, found = rl.clients[client]
+		if !found {
+			bucket = newTokenBucket(rl.cfg.ClientRPS, rl.cfg.ClientBurst, now)
+			rl.clients[client] = bucket
+		}
+		if ok, wait := bucket.take(now); !ok {
+			return false, scopeClient, wait
+		}
+	}
+
+	if rl.cfg.RPS > 0 {
+		if rl.global == nil {
+			rl.global = newTokenBucket(rl.cfg.RPS, rl.cfg.Burst, now)
+		}
+		if ok, wait := rl.global.take(now); !ok {
+			if bucket != nil {
+				bucket.refund()
+			}
+			return false, scopeGlobal, wait
+		}
+	}
+
+	return true, "", 0
+}
+
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < clientIdleTimeout {
+		return
+	}
+	rl.lastSweep = now
+
+	for client, bucket := range rl.clients {
+		if now.Sub(bucket.lastSeen) > clientIdleTimeout {
+			delete(rl.clients, client)
+		}
+	}
+}
+
+func (rl *RateLimiter) clientID(request *http.Request) string {
+	if rl.auth != nil {
+		if id, ok := rl.auth.verify(request); ok {
+			return "client:" + id
+		}
+	}
+
+	return "ip:" + clientIP(request)
+}
+
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	if !rl.cfg.Enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		ok, scope, wait := rl.allow(rl.clientID(request))
+		if ok {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+		attrs := []attribute.KeyValue{
+			attribute.String("ratelimit.scope", scope),
+			semconv.ClientAddress(clientIP(request)),
+		}
+		_, span := rl.tracer.Start(ctx, "service_a:rate_limited", trace.WithAttributes(attrs...))
+		span.AddEvent("request throttled", trace.WithAttributes(
+			attribute.Float64("ratelimit.retry_after_seconds", wait.Seconds()),
+		))
+		span.SetAttributes(semconv.HTTPResponseStatusCode(http.StatusTooManyRequests))
+		span.End()
+		rl.throttled.Add(ctx, 1, metric.WithAttributes(attribute.String("ratelimit.scope", scope)))
+
+		writer.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(writer, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	})
+}
+
+func clientIP(request *http.Request) string {
+	host, _, splitErr := net.SplitHostPort(request.RemoteAddr)
+	if splitErr != nil {
+		return request.RemoteAddr
+	}
+
+	return host
+}
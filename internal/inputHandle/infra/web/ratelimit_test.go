@@ -0,0 +1,140 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func newTestAuthenticator(keys map[string]string) *Authenticator {
+	cfg := config.Auth{Enabled: true}
+	for id, key := range keys {
+		sum := sha256.Sum256([]byte(key))
+		cfg.Keys = append(cfg.Keys, config.APIKey{ID: id, Hash: hex.EncodeToString(sum[:])})
+	}
+
+	return NewAuthenticator(cfg)
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewRateLimiter(config.RateLimit{
+		Enabled:     true,
+		RPS:         100,
+		Burst:       100,
+		ClientRPS:   1,
+		ClientBurst: 2,
+	}, noop.NewTracerProvider().Tracer(""), WithAuthenticator(newTestAuthenticator(map[string]string{
+		"team-a": "key-a",
+		"team-b": "key-b",
+	})))
+	limiter.now = func() time.Time { return now }
+
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	call := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/getCep", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, call("key-a").Code)
+	assert.Equal(t, http.StatusOK, call("key-a").Code)
+
+	throttled := call("key-a")
+	assert.Equal(t, http.StatusTooManyRequests, throttled.Code)
+	assert.Equal(t, "1", throttled.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, call("key-b").Code, "other clients keep their own quota")
+
+	assert.Equal(t, http.StatusOK, call("made-up-1").Code)
+	assert.Equal(t, http.StatusOK, call("made-up-2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, call("made-up-3").Code, "unknown keys share their IP's bucket")
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, call("key-a").Code, "bucket refills over time")
+}
+
+func TestRateLimiterKeepsGlobalTokensForOtherClients(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimit{
+		Enabled:     true,
+		RPS:         1,
+		Burst:       2,
+		ClientRPS:   1,
+		ClientBurst: 1,
+	}, noop.NewTracerProvider().Tracer(""))
+	limiter.now = func() time.Time { return time.Unix(0, 0) }
+
+	ok, _, _ := limiter.allow("ip:10.0.0.1")
+	assert.True(t, ok)
+	for i := 0; i < 5; i++ {
+		ok, scope, _ := limiter.allow("ip:10.0.0.1")
+		assert.False(t, ok)
+		assert.Equal(t, scopeClient, scope)
+	}
+
+	ok, _, _ = limiter.allow("ip:10.0.0.2")
+	assert.True(t, ok, "a client over its quota must not drain the global bucket")
+}
+
+func TestRateLimiterGlobalLimit(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimit{Enabled: true, RPS: 1, Burst: 1, ClientRPS: 1, ClientBurst: 1}, noop.NewTracerProvider().Tracer(""))
+	now := time.Unix(0, 0)
+	limiter.now = func() time.Time { return now }
+
+	ok, _, _ := limiter.allow("ip:10.0.0.1")
+	assert.True(t, ok)
+
+	ok, scope, wait := limiter.allow("ip:10.0.0.2")
+	assert.False(t, ok)
+	assert.Equal(t, scopeGlobal, scope)
+	assert.Equal(t, time.Second, wait)
+
+	now = now.Add(time.Second)
+	ok, _, _ = limiter.allow("ip:10.0.0.2")
+	assert.True(t, ok, "the client token taken by the rejected request is refunded")
+}
+
+func TestRateLimiterRecordsThrottledRequests(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer provider.Shutdown(context.Background())
+
+	limiter := NewRateLimiter(config.RateLimit{Enabled: true, ClientRPS: 1, ClientBurst: 1},
+		noop.NewTracerProvider().Tracer(""), WithMeterProvider(provider))
+	limiter.now = func() time.Time { return time.Unix(0, 0) }
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for i := 0; i < 3; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/getCep", nil))
+	}
+
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &metrics))
+	require.Len(t, metrics.ScopeMetrics, 1)
+	require.Len(t, metrics.ScopeMetrics[0].Metrics, 1)
+
+	throttled := metrics.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "service_a.requests.throttled", throttled.Name)
+	sum, ok := throttled.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(2), sum.DataPoints[0].Value)
+	scope, _ := sum.DataPoints[0].Attributes.Value(attribute.Key("ratelimit.scope"))
+	assert.Equal(t, scopeClient, scope.AsString())
+}
@@ -2,99 +2,109 @@ package web
 
 import (
 	"encoding/json"
-	"io"
 	"net/http"
 	"time"
 
-	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/dto"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/httpserver"
 	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
 )
 
-func (gr *Server) temperature(writer http.ResponseWriter, request *http.Request) {
-	carrier := propagation.HeaderCarrier(request.Header)
-	ctx := request.Context()
-	ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
+// cepSource extracts the raw CEP from a request. Its errors are answered by
+// writeRequestError.
+type cepSource func(request *http.Request) (string, error)
 
-	ctx, spanFn := gr.TemplateData.OTELTracer.Start(ctx, gr.TemplateData.RequestNameOtel)
-	defer spanFn.End()
-
-	bodyBytes, readErr := io.ReadAll(request.Body)
-	if readErr != nil {
-		http.Error(writer, readErr.Error(), http.StatusBadRequest)
-		return
+// cepFromBody decodes the CEPRequest body of POST /getCep strictly.
+func cepFromBody(request *http.Request) (string, error) {
+	var location contract.CEPRequest
+	if err := decodeJSON(request.Body, &location); err != nil {
+		return "", err
 	}
 
-	var location dto.LocationInput
-	if unmErr := json.Unmarshal(bodyBytes, &location); unmErr != nil {
-		http.Error(writer, "Error parsing JSON", http.StatusBadRequest)
-		return
-	}
+	return location.CEP, nil
+}
 
-	if err := entity.CEPValidation(location.CEP); err != nil {
-		http.Error(writer, err.Error(), http.StatusUnprocessableEntity)
-		return
-	}
+// cepFromPath reads the CEP of GET /temperature/{cep}.
+func cepFromPath(request *http.Request) (string, error) {
+	return request.PathValue(contract.PathCEP), nil
+}
 
-	reqCtx, reqCtxErr := http.NewRequestWithContext(
-		ctx,
-		http.MethodGet,
-		"http://service_b:50055/temperature?cep="+location.CEP,
-		nil,
-	)
-	if reqCtxErr != nil {
-		http.Error(writer, reqCtxErr.Error(), http.StatusInternalServerError)
-		return
-	}
+// cepFromQuery reads the CEP of GET /temperature?cep=.
+func cepFromQuery(request *http.Request) (string, error) {
+	return request.URL.Query().Get(contract.QueryCEP), nil
+}
 
-	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(reqCtx.Header))
-	httpClient := http.Client{
-		Timeout: time.Second * 10,
-	}
+// temperature answers with the temperature of the CEP taken from the request
+// by source, so every route validates and traces it the same way. GET
+// answers carry caching headers and honour conditional requests.
+func (gr *Server) temperature(source cepSource) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		carrier := propagation.HeaderCarrier(request.Header)
+		ctx := request.Context()
+		ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
 
-	clientDo, doErr := httpClient.Do(reqCtx)
-	if doErr != nil {
-		http.Error(writer, doErr.Error(), http.StatusInternalServerError)
-		return
-	}
-	defer clientDo.Body.Close()
-
-	switch clientDo.StatusCode {
-	case http.StatusUnprocessableEntity:
-		http.Error(writer, entity.ErrCEPNotValid.Error(), http.StatusUnprocessableEntity)
-		return
-	case http.StatusNotFound:
-		http.Error(writer, entity.ErrCEPNotFound.Error(), http.StatusNotFound)
-		return
-	}
+		ctx, spanFn := gr.TemplateData.OTELTracer.Start(ctx, gr.TemplateData.RequestNameOtel)
+		defer httpserver.EndSpan(spanFn)
+		ctx = withClientIdentity(ctx, spanFn)
 
-	locRespBody, bReadErr := io.ReadAll(clientDo.Body)
-	if bReadErr != nil {
-		http.Error(writer, bReadErr.Error(), http.StatusInternalServerError)
-		return
-	}
+		rawCEP, sourceErr := source(request)
+		if sourceErr != nil {
+			writeRequestError(writer, sourceErr)
+			return
+		}
 
-	writer.Header().Set("Content-Type", "application/json")
-	var locTempResp dto.TemperatureAPIOutput
-	if err := json.Unmarshal(locRespBody, &locTempResp); err != nil {
-		http.Error(writer, err.Error(), http.StatusInternalServerError)
-		return
-	}
+		cep, cepErr := entity.ParseCEP(rawCEP)
+		if cepErr != nil {
+			contract.WriteError(writer, cepErr)
+			return
+		}
 
-	jsonData, marshErr := json.Marshal(dto.TemperatureOutput{
-		City:  locTempResp.Location,
-		TempC: locTempResp.TempC,
-		TempF: locTempResp.TempF,
-		TempK: locTempResp.TempK,
-	})
-	if marshErr != nil {
-		http.Error(writer, "Error generating JSON", http.StatusInternalServerError)
-		return
-	}
+		units, unitErr := entity.ParseUnits(request.URL.Query().Get(contract.QueryUnits))
+		if unitErr != nil {
+			contract.WriteError(writer, unitErr)
+			return
+		}
+
+		lang := entity.NegotiateLanguage(request.Header.Get("Accept-Language"))
+		spanFn.SetAttributes(
+			attribute.String("temperature.units", entity.JoinUnits(units)),
+			attribute.String("temperature.language", string(lang)),
+		)
+
+		locTempResp, tempErr := gr.TemperatureClient.Temperature(ctx, contract.TemperatureRequest{
+			CEP:      cep.String(),
+			Units:    entity.JoinUnits(units),
+			Language: string(lang),
+		})
+		if tempErr != nil {
+			contract.WriteError(writer, tempErr)
+			return
+		}
+
+		jsonData, marshErr := json.Marshal(locTempResp.CEPResponse())
+		if marshErr != nil {
+			http.Error(writer, "Error generating JSON", http.StatusInternalServerError)
+			return
+		}
+
+		writer.Header().Set("Content-Language", string(lang))
+		if request.Method == http.MethodGet || request.Method == http.MethodHead {
+			_, authenticated := ClientID(ctx)
+			notModified := setCacheHeaders(writer.Header(), request, jsonData, locTempResp.LastUpdated, authenticated, time.Now())
+			spanFn.SetAttributes(attribute.Bool("http.response.not_modified", notModified))
+			if notModified {
+				writer.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
 
-	if _, wErr := writer.Write(jsonData); wErr != nil {
-		http.Error(writer, "Error writing", http.StatusInternalServerError)
-		return
+		writer.Header().Set("Content-Type", "application/json")
+		if _, wErr := writer.Write(jsonData); wErr != nil {
+			http.Error(writer, "Error writing", http.StatusInternalServerError)
+			return
+		}
 	}
 }
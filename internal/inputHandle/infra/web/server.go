@@ -3,25 +3,73 @@ package web
 import (
 	"log"
 	"net/http"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/httpserver"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/serviceb"
+	"github.com/MatheusBenetti/opentelemetry/internal/openapi"
 )
 
+type Middleware func(http.Handler) http.Handler
+
+// DefaultPort is where Execute listens when Server.Port is unset.
+const DefaultPort = "8080"
+
 type Server struct {
-	TemplateData TemplateData
-	mux          *http.ServeMux
+	TemplateData      TemplateData
+	Port              string // DefaultPort when unset
+	Middlewares       []Middleware
+	MaxBodyBytes      int64 // DefaultMaxBodyBytes when unset
+	HTTP              config.HTTPServer
+	TemperatureClient serviceb.Client
+	OpenAPI           *openapi.Spec
+	mux               *http.ServeMux
+	handler           http.Handler
 }
 
 func (gr *Server) prepare() {
+	if gr.TemperatureClient == nil {
+		gr.TemperatureClient = contract.NewClient(gr.TemplateData.ExternalCallURL)
+	}
+
 	gr.mux = http.NewServeMux()
-	gr.mux.HandleFunc("POST /getCep", gr.temperature)
+	gr.mux.HandleFunc("POST "+contract.GetCepPath, gr.temperature(cepFromBody))
+	gr.mux.HandleFunc("GET "+contract.TemperatureByCEPPath, gr.temperature(cepFromPath))
+	gr.mux.HandleFunc("GET "+contract.TemperaturePath, gr.temperature(cepFromQuery))
+
+	gr.handler = gr.mux
+	if gr.OpenAPI != nil {
+		gr.mux.Handle("GET "+openapi.Path, gr.OpenAPI)
+		gr.handler = gr.OpenAPI.Middleware(gr.handler)
+	}
+	gr.handler = checkBody(http.MethodPost, contract.GetCepPath, cepFromBody)(gr.handler)
+	gr.handler = limitBody(gr.MaxBodyBytes)(gr.handler)
+	for i := len(gr.Middlewares) - 1; i >= 0; i-- {
+		gr.handler = gr.Middlewares[i](gr.handler)
+	}
+	gr.handler = httpserver.Recover(gr.handler)
 }
 
 func (gr *Server) run() {
-	if err := http.ListenAndServe(":8080", gr.mux); err != nil {
+	port := gr.Port
+	if port == "" {
+		port = DefaultPort
+	}
+
+	if err := httpserver.New(":"+port, gr.handler, gr.HTTP).ListenAndServe(); err != nil {
 		log.Println("server error", err)
 		return
 	}
 }
 
+// Handler builds the server's routes and middleware chain, so it can be
+// mounted elsewhere, such as on an httptest.Server.
+func (gr *Server) Handler() http.Handler {
+	gr.prepare()
+	return gr.handler
+}
+
 func (gr *Server) Execute() {
 	gr.prepare()
 	gr.run()
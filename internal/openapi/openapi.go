@@ -0,0 +1,203 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+const Path = "/openapi.json"
+
+var (
+	//go:embed service_a.json
+	serviceASpec []byte
+
+	//go:embed service_b.json
+	serviceBSpec []byte
+)
+
+// Spec is an OpenAPI document along with the raw JSON it was loaded from.
+type Spec struct {
+	Doc    *openapi3.T
+	raw    []byte
+	router routers.Router
+}
+
+func ServiceA() (*Spec, error) {
+	return load(serviceASpec)
+}
+
+func ServiceB() (*Spec, error) {
+	return load(serviceBSpec)
+}
+
+func load(raw []byte) (*Spec, error) {
+	doc, loadErr := openapi3.NewLoader().LoadFromData(raw)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	router, routerErr := legacy.NewRouter(doc)
+	if routerErr != nil {
+		return nil, routerErr
+	}
+
+	return &Spec{
+		Doc:    doc,
+		raw:    raw,
+		router: router,
+	}, nil
+}
+
+// ServeHTTP serves the spec, meant to be routed at Path.
+func (s *Spec) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", "application/json")
+	if _, wErr := writer.Write(s.raw); wErr != nil {
+		http.Error(writer, "Error writing", http.StatusInternalServerError)
+	}
+}
+
+// Middleware rejects requests that don't match the spec with a 400, naming
+// the failing fields and parameters, and logs the detailed error along with
+// responses that don't match it. Routes missing from the spec pass through
+// untouched so the mux can answer them.
+func (s *Spec) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		route, pathParams, routeErr := s.router.FindRoute(request)
+		if routeErr != nil {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		reqInput := &openapi3filter.RequestValidationInput{
+			Request:    request,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				MultiError:         true,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(request.Context(), reqInput); err != nil {
+			log.Printf("request %s %s does not match the OpenAPI spec: %s\n", request.Method, request.URL.Path, err)
+			http.Error(writer, validationMessage(err), http.StatusBadRequest)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: writer, status: http.StatusOK}
+		next.ServeHTTP(recorder, request)
+
+		respInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: reqInput,
+			Status:                 recorder.status,
+			Header:                 writer.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+		}
+		if err := openapi3filter.ValidateResponse(context.WithoutCancel(request.Context()), respInput); err != nil {
+			log.Printf("response for %s %s does not match the OpenAPI spec: %s\n", request.Method, request.URL.Path, err)
+		}
+	})
+}
+
+// validationMessage describes err by the fields and parameters that failed,
+// one per line. kin-openapi's own messages embed the schema and the value
+// sent, so they are only logged.
+func validationMessage(err error) string {
+	return strings.Join(validationMessages(err), "\n")
+}
+
+func validationMessages(err error) []string {
+	var messages []string
+	for _, e := range flatten(err) {
+		var reqErr *openapi3filter.RequestError
+		if !errors.As(e, &reqErr) {
+			messages = append(messages, "request does not match the API specification")
+			continue
+		}
+
+		subject := "request"
+		switch {
+		case reqErr.Parameter != nil:
+			subject = fmt.Sprintf("%s parameter %q", reqErr.Parameter.In, reqErr.Parameter.Name)
+		case reqErr.RequestBody != nil:
+			subject = "request body"
+		}
+
+		for _, cause := range flatten(reqErr.Err) {
+			messages = append(messages, causeMessage(subject, cause))
+		}
+	}
+
+	return messages
+}
+
+func causeMessage(subject string, cause error) string {
+	var schemaErr *openapi3.SchemaError
+	switch {
+	case errors.As(cause, &schemaErr):
+		field := strings.Join(schemaErr.JSONPointer(), ".")
+		switch {
+		case field == "":
+		case schemaErr.SchemaField == "required":
+			return fmt.Sprintf("field %q is required", field)
+		default:
+			subject = fmt.Sprintf("field %q", field)
+		}
+		return subject + ": " + schemaErr.Reason
+	case errors.Is(cause, openapi3filter.ErrInvalidRequired):
+		return subject + " is required"
+	case errors.Is(cause, openapi3filter.ErrInvalidEmptyValue):
+		return subject + " must not be empty"
+	default:
+		return subject + " is invalid"
+	}
+}
+
+// flatten lists the errors gathered in err when it is a MultiError. Wrapped
+// MultiErrors are left alone, so each RequestError keeps its subject.
+func flatten(err error) []error {
+	multi, ok := err.(openapi3.MultiError)
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range multi {
+		errs = append(errs, flatten(e)...)
+	}
+
+	return errs
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
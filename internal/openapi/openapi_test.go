@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jsonType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return openapi3.TypeString
+	}
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return openapi3.TypeNumber
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return openapi3.TypeInteger
+	case reflect.Bool:
+		return openapi3.TypeBoolean
+	case reflect.Slice:
+		return openapi3.TypeArray
+	case reflect.Struct, reflect.Map:
+		return openapi3.TypeObject
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	default:
+		return openapi3.TypeString
+	}
+}
+
+func assertMatchesSchema(t *testing.T, value any, schema *openapi3.Schema) {
+	t.Helper()
+
+	typ := reflect.TypeOf(value)
+	var fields, required []string
+	for i := 0; i < typ.NumField(); i++ {
+		name, opts, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields = append(fields, name)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+
+		prop, ok := schema.Properties[name]
+		if assert.Truef(t, ok, "field %q of %s is missing from the spec", name, typ.Name()) {
+			assert.Equalf(t, prop.Value.Type, jsonType(typ.Field(i).Type), "type of %s.%s", typ.Name(), name)
+		}
+	}
+
+	var props []string
+	for name := range schema.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(fields)
+	sort.Strings(props)
+	sort.Strings(required)
+	specRequired := append([]string(nil), schema.Required...)
+	sort.Strings(specRequired)
+
+	assert.Equal(t, props, fields, "spec properties of %s", typ.Name())
+	assert.Equal(t, specRequired, required, "required properties of %s", typ.Name())
+
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+	var decoded any
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.NoError(t, schema.VisitJSON(decoded))
+}
+
+func TestContract(t *testing.T) {
+	serviceA, err := ServiceA()
+	require.NoError(t, err)
+	serviceB, err := ServiceB()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		value  any
+		schema *openapi3.Schema
+	}{
+		{"service A input", contract.CEPRequest{CEP: "95670084"}, serviceA.Doc.Components.Schemas["LocationInput"].Value},
+		{"service A output", contract.CEPResponse{City: "Gramado"}, serviceA.Doc.Components.Schemas["TemperatureOutput"].Value},
+		{"service B output", contract.TemperatureResponse{Location: "Gramado"}, serviceB.Doc.Components.Schemas["TemperatureOutput"].Value},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertMatchesSchema(t, tt.value, tt.schema)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	spec, err := ServiceA()
+	require.NoError(t, err)
+
+	handler := spec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		expected int
+		message  string
+	}{
+		{"valid body", http.MethodPost, "/getCep", `{"cep":"95670084"}`, http.StatusTeapot, ""},
+		{"missing cep", http.MethodPost, "/getCep", `{}`, http.StatusBadRequest, `field "cep" is required`},
+		{"unknown field", http.MethodPost, "/getCep", `{"cep":"95670084","city":"Gramado"}`, http.StatusBadRequest, `request body: property "city" is unsupported`},
+		{"wrong type", http.MethodPost, "/getCep", `{"cep":95670084}`, http.StatusBadRequest, `field "cep": value must be a string`},
+		{"missing query parameter", http.MethodGet, "/temperature", "", http.StatusBadRequest, `query parameter "cep" is required`},
+		{"route outside the spec", http.MethodGet, Path, "", http.StatusTeapot, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expected, rec.Code)
+			if tt.message != "" {
+				assert.Equal(t, tt.message, strings.TrimSpace(rec.Body.String()))
+			}
+		})
+	}
+}
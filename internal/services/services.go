@@ -0,0 +1,106 @@
+// Package services builds service A and service B from the config, so the
+// cmd binaries, the single process in the module root, tempctl and the e2e
+// harness all wire them the same way.
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/serviceb"
+	inputWeb "github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/web"
+	"github.com/MatheusBenetti/opentelemetry/internal/openapi"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/api"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/cepdb"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/grpcapi"
+	tempWeb "github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/web"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Names of the spans each service starts for a request.
+const (
+	ServiceASpan = "service_a:all"
+	ServiceBSpan = "service_b:all"
+)
+
+// GetWeather builds service B's use case on the CEP ranges and upstreams of
+// cfg, with the repositories tracing through provider. Invalid precisions are
+// logged and left at the default.
+func GetWeather(cfg *config.Config, provider trace.TracerProvider) (usecase.GetWeather, error) {
+	cepDB, dbErr := cepdb.Open(cfg.CEP.RangesFile)
+	if dbErr != nil {
+		return usecase.GetWeather{}, fmt.Errorf("failed loading the CEP ranges: %w", dbErr)
+	}
+
+	precision, skipped := entity.PrecisionFromNames(cfg.Temperature.Precision)
+	for _, err := range skipped {
+		log.Printf("ignoring precision: %s\n", err)
+	}
+
+	return usecase.NewGetWeather(
+		cepdb.NewLocationRepository(cepDB, api.NewCEPFromAPI(cfg.CEP.URL, api.WithTracerProvider(provider))),
+		api.NewWeatherFromAPI(cfg.Temperature.URL, cfg.Temperature.CurrentAPIKey, api.WithTracerProvider(provider)),
+		precision,
+	), nil
+}
+
+// ServiceB returns service B's HTTP server for gw, validated against its
+// OpenAPI spec.
+func ServiceB(cfg *config.Config, gw usecase.GetWeather, provider trace.TracerProvider) (*tempWeb.Server, error) {
+	spec, specErr := openapi.ServiceB()
+	if specErr != nil {
+		return nil, fmt.Errorf("failed loading service B's OpenAPI spec: %w", specErr)
+	}
+
+	return &tempWeb.Server{
+		Port:            cfg.ServiceB.Port,
+		RequestNameOtel: ServiceBSpan,
+		OTELTracer:      provider.Tracer("service_b"),
+		GetWeather:      gw,
+		OpenAPI:         spec,
+		HTTP:            cfg.ServiceB.HTTP,
+	}, nil
+}
+
+// ServiceBGRPC returns service B's gRPC server for gw.
+func ServiceBGRPC(gw usecase.GetWeather, provider trace.TracerProvider) *grpcapi.Server {
+	return grpcapi.NewServer(gw, provider, ServiceBSpan)
+}
+
+// ServiceA returns service A's HTTP server, reaching service B through
+// client, with the rate limiter and authentication of cfg. The throttled
+// requests are counted through meterProvider, the global one when nil.
+func ServiceA(cfg *config.Config, client serviceb.Client, provider trace.TracerProvider, meterProvider metric.MeterProvider) (*inputWeb.Server, error) {
+	spec, specErr := openapi.ServiceA()
+	if specErr != nil {
+		return nil, fmt.Errorf("failed loading service A's OpenAPI spec: %w", specErr)
+	}
+
+	tracer := provider.Tracer("service_a")
+	authenticator := inputWeb.NewAuthenticator(cfg.ServiceA.Auth)
+
+	return &inputWeb.Server{
+		TemplateData: inputWeb.TemplateData{
+			Title:           "Service A: Orchestration",
+			ExternalCallURL: cfg.ServiceB.Host,
+			RequestNameOtel: ServiceASpan,
+			OTELTracer:      tracer,
+		},
+		Port:              cfg.ServiceA.Port,
+		TemperatureClient: client,
+		OpenAPI:           spec,
+		MaxBodyBytes:      cfg.ServiceA.MaxBodyBytes,
+		HTTP:              cfg.ServiceA.HTTP,
+		Middlewares: []inputWeb.Middleware{
+			inputWeb.NewRateLimiter(cfg.ServiceA.RateLimit, tracer,
+				inputWeb.WithAuthenticator(authenticator),
+				inputWeb.WithMeterProvider(meterProvider),
+			).Middleware,
+			authenticator.Middleware,
+		},
+	}, nil
+}
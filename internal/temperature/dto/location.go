@@ -1,7 +1,9 @@
 package dto
 
 type LocationInput struct {
-	CEP string `json:"cep"`
+	CEP      string `json:"cep"`
+	Units    string `json:"units"`
+	Language string `json:"language"`
 }
 
 type LocationOut struct {
@@ -17,3 +19,16 @@ type LocationOut struct {
 	Siafi       string      `json:"siafi"`
 	ErrorOut    interface{} `json:"erro"`
 }
+
+// NotFound reports whether ViaCEP flagged the CEP as unknown. Older answers
+// send "erro": true and newer ones "erro": "true".
+func (l LocationOut) NotFound() bool {
+	switch value := l.ErrorOut.(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	default:
+		return false
+	}
+}
@@ -1,5 +1,7 @@
 package dto
 
+import contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+
 type TemperatureInput struct {
 	ApiKey     string
 	QueryCity  string
@@ -11,6 +13,14 @@ type TemperatureResponseOut struct {
 	Current  TemperatureCurrent  `json:"current"`
 }
 
+// TemperatureErrorOut is the envelope weatherapi.com answers with on errors.
+type TemperatureErrorOut struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
 type TemperatureLocation struct {
 	Name           string  `json:"name"`
 	Region         string  `json:"region"`
@@ -52,9 +62,6 @@ type TemperatureCurrent struct {
 	GustKph    float64 `json:"gust_kph"`
 }
 
-type TemperatureOutput struct {
-	Location string  `json:"location"`
-	TempC    float64 `json:"temp_C"`
-	TempF    float64 `json:"temp_F"`
-	TempK    float64 `json:"temp_K"`
-}
+// TemperatureOutput is what service B answers with, fixed by the v1
+// contract.
+type TemperatureOutput = contract.TemperatureResponse
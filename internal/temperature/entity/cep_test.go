@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCEP(t *testing.T) {
+	tests := []struct {
+		input     string
+		canonical string
+		formatted string
+		err       error
+	}{
+		{"95670084", "95670084", "95670-084", nil},
+		{"95670-084", "95670084", "95670-084", nil},
+		{" 95670084 ", "95670084", "95670-084", nil},
+		{"95.670-084", "95670084", "95670-084", nil},
+		{"\t95.670-084\n", "95670084", "95670-084", nil},
+		{"95.670084", "95670084", "95670-084", nil},
+		{"", "", "", ErrCEPNotValid},
+		{"9567008", "", "", ErrCEPNotValid},
+		{"956700845", "", "", ErrCEPNotValid},
+		{"95670/084", "", "", ErrCEPNotValid},
+		{"9567o084", "", "", ErrCEPNotValid},
+		{"９５６７００８４", "", "", ErrCEPNotValid},
+		{"9-5-6-7-0-0-8-4", "", "", ErrCEPNotValid},
+		{"95670 084", "", "", ErrCEPNotValid},
+		{"--95670.084--", "", "", ErrCEPNotValid},
+		{"956.70-084", "", "", ErrCEPNotValid},
+		{"95670--084", "", "", ErrCEPNotValid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			cep, err := ParseCEP(tt.input)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.canonical, cep.String())
+			if tt.err == nil {
+				assert.Equal(t, tt.formatted, cep.Formatted())
+				assert.NoError(t, CEPValidation(tt.input))
+			} else {
+				assert.ErrorIs(t, CEPValidation(tt.input), ErrCEPNotValid)
+			}
+		})
+	}
+}
+
+func FuzzParseCEP(f *testing.F) {
+	for _, seed := range []string{"95670084", "95670-084", " 95.670-084 ", "", "abc", "00000-000", "9-5-6-7-0-0-8-4", "95670 084", "--95670.084--"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		cep, err := ParseCEP(input)
+		if err != nil {
+			assert.ErrorIs(t, err, ErrCEPNotValid)
+			assert.Empty(t, cep)
+			return
+		}
+
+		assert.Regexp(t, `^\d{2}\.?\d{3}-?\d{3}$`, strings.TrimSpace(input), "only the accepted formats may parse")
+		assert.Len(t, cep.String(), 8)
+		for _, r := range cep.String() {
+			assert.True(t, unicode.IsDigit(r) && r < unicode.MaxASCII, "canonical CEP must only hold ASCII digits")
+		}
+
+		fromCanonical, canonicalErr := ParseCEP(cep.String())
+		assert.NoError(t, canonicalErr)
+		assert.Equal(t, cep, fromCanonical)
+
+		fromFormatted, formattedErr := ParseCEP(cep.Formatted())
+		assert.NoError(t, formattedErr)
+		assert.Equal(t, cep, fromFormatted)
+	})
+}
@@ -3,7 +3,17 @@ package entity
 import "errors"
 
 var (
-	ErrCEPNotFound = errors.New("can not found zipcode")
-	ErrCEPNotValid = errors.New("invalid zipcode")
-	ErrEmptyAPIkey = errors.New("you should provide a not empty API key")
+	ErrCEPNotFound  = errors.New("can not found zipcode")
+	ErrCEPNotValid  = errors.New("invalid zipcode")
+	ErrEmptyAPIkey  = errors.New("you should provide a not empty API key")
+	ErrUnitNotValid = errors.New("invalid temperature unit")
+
+	ErrCEPProviderBadRequest  = errors.New("zipcode provider rejected the request")
+	ErrCEPProviderUnavailable = errors.New("zipcode provider unavailable")
+	ErrCEPProviderRateLimited = errors.New("zipcode provider rate limit exceeded")
+
+	ErrWeatherLocationNotFound = errors.New("can not found weather for location")
+	ErrWeatherAPIKeyInvalid    = errors.New("weather provider rejected the API key")
+	ErrWeatherQuotaExceeded    = errors.New("weather provider quota exceeded")
+	ErrWeatherUnavailable      = errors.New("weather provider unavailable")
 )
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"golang.org/x/text/language"
+)
+
+// Language is the BCP 47 tag of a language the weather provider can answer in.
+type Language string
+
+const DefaultLanguage Language = "en"
+
+// supportedLanguages lists the languages weatherapi.com translates condition
+// texts to. The first entry is the fallback used by the matcher.
+var supportedLanguages = []Language{
+	DefaultLanguage, "ar", "bn", "bg", "zh-Hans", "zh-Hant", "cs", "da", "nl",
+	"fi", "fr", "de", "el", "hi", "hu", "it", "ja", "jv", "ko", "mr", "pl",
+	"pt", "pa", "ro", "ru", "sr", "si", "sk", "es", "sv", "ta", "te", "tr",
+	"uk", "ur", "vi", "zu",
+}
+
+var languageMatcher = func() language.Matcher {
+	tags := make([]language.Tag, len(supportedLanguages))
+	for i, lang := range supportedLanguages {
+		tags[i] = language.MustParse(string(lang))
+	}
+
+	return language.NewMatcher(tags)
+}()
+
+// NegotiateLanguage picks the best supported language for an Accept-Language
+// header value, falling back to DefaultLanguage.
+func NegotiateLanguage(acceptLanguage string) Language {
+	tags, _, parseErr := language.ParseAcceptLanguage(acceptLanguage)
+	if parseErr != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+
+	_, index, confidence := languageMatcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLanguage
+	}
+
+	return supportedLanguages[index]
+}
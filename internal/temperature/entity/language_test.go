@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		expected       Language
+	}{
+		{"", DefaultLanguage},
+		{"pt-BR,pt;q=0.9,en;q=0.8", "pt"},
+		{"en-US", DefaultLanguage},
+		{"fr-CA;q=0.5, de;q=0.9", "de"},
+		{"zh-TW", "zh-Hant"},
+		{"zh-CN", "zh-Hans"},
+		{"xx-YY", DefaultLanguage},
+		{"not a header;;", DefaultLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NegotiateLanguage(tt.acceptLanguage))
+		})
+	}
+}
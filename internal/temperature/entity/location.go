@@ -1,17 +1,51 @@
 package entity
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+)
+
+// cepPattern accepts the CEP as eight digits or formatted by the post office,
+// NNNNN-NNN, optionally with a dot after the second digit.
+var cepPattern = regexp.MustCompile(`^\d{2}\.?\d{3}-?\d{3}$`)
+
+var cepSeparators = strings.NewReplacer("-", "", ".", "")
 
 type Location struct {
 	Cep        string
 	Localidade string
 }
 
-func CEPValidation(cep string) error {
-	re := regexp.MustCompile(`^\d{8}$`)
-	if !re.MatchString(cep) {
-		return ErrCEPNotValid
+// CEP is a validated zipcode in its canonical form: eight digits with no
+// separators.
+type CEP string
+
+// ParseCEP normalises the ways users usually type a CEP ("95670-084",
+// "95.670-084", " 95670084 ") into its canonical form. Only surrounding
+// whitespace is ignored; separators anywhere else are rejected.
+func ParseCEP(raw string) (CEP, error) {
+	cep := strings.TrimSpace(raw)
+	if !cepPattern.MatchString(cep) {
+		return "", ErrCEPNotValid
 	}
 
-	return nil
+	return CEP(cepSeparators.Replace(cep)), nil
+}
+
+func (c CEP) String() string {
+	return string(c)
+}
+
+// Formatted returns the CEP as printed by the post office, e.g. 95670-084.
+func (c CEP) Formatted() string {
+	if len(c) != 8 {
+		return string(c)
+	}
+
+	return string(c[:5]) + "-" + string(c[5:])
+}
+
+func CEPValidation(cep string) error {
+	_, err := ParseCEP(cep)
+	return err
 }
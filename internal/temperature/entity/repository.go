@@ -9,5 +9,5 @@ type LocationRepository interface {
 }
 
 type TemperatureRepository interface {
-	Get(ctx context.Context, location string) (Temperature, error)
+	Get(ctx context.Context, location string, lang Language) (Temperature, error)
 }
@@ -1,9 +1,130 @@
 package entity
 
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	absoluteZeroCelsius = 273.15
+
+	defaultPrecision = 2
+
+	// MaxPrecision is the most decimal places a unit can be rounded to. Past
+	// it math.Pow10 overflows for large readings and Round returns NaN.
+	MaxPrecision = 6
+)
+
+type Unit string
+
+const (
+	Celsius    Unit = "C"
+	Fahrenheit Unit = "F"
+	Kelvin     Unit = "K"
+	Rankine    Unit = "R"
+	Reaumur    Unit = "Re"
+)
+
+// DefaultUnits are returned when the caller doesn't ask for specific units.
+var DefaultUnits = []Unit{Celsius, Fahrenheit, Kelvin}
+
+var unitAliases = map[string]Unit{
+	"c":          Celsius,
+	"celsius":    Celsius,
+	"f":          Fahrenheit,
+	"fahrenheit": Fahrenheit,
+	"k":          Kelvin,
+	"kelvin":     Kelvin,
+	"r":          Rankine,
+	"rankine":    Rankine,
+	"re":         Reaumur,
+	"reaumur":    Reaumur,
+	"réaumur":    Reaumur,
+}
+
+// ParseUnits reads a comma separated list of unit codes or names, such as
+// "C,F" or "celsius,rankine". An empty list means DefaultUnits.
+func ParseUnits(raw string) ([]Unit, error) {
+	if strings.TrimSpace(raw) == "" {
+		return DefaultUnits, nil
+	}
+
+	var units []Unit
+	seen := make(map[Unit]bool)
+	for _, part := range strings.Split(raw, ",") {
+		unit, ok := unitAliases[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return nil, ErrUnitNotValid
+		}
+		if !seen[unit] {
+			seen[unit] = true
+			units = append(units, unit)
+		}
+	}
+
+	return units, nil
+}
+
+// JoinUnits is the inverse of ParseUnits.
+func JoinUnits(units []Unit) string {
+	codes := make([]string, len(units))
+	for i, unit := range units {
+		codes[i] = string(unit)
+	}
+
+	return strings.Join(codes, ",")
+}
+
+// Precision holds how many decimal places each unit is rounded to. Units
+// missing from the map use two decimal places.
+type Precision map[Unit]int
+
+// PrecisionFromNames builds a Precision from decimal places keyed by unit code
+// or name, as in the config. Keys that aren't a single known unit and values
+// outside 0 to MaxPrecision are skipped, and returned as errors so the caller
+// can report them.
+func PrecisionFromNames(places map[string]int) (Precision, []error) {
+	precision := Precision{}
+	var skipped []error
+	for name, value := range places {
+		units, err := ParseUnits(name)
+		if err != nil || len(units) != 1 {
+			skipped = append(skipped, fmt.Errorf("unknown unit %q", name))
+			continue
+		}
+		if value < 0 || value > MaxPrecision {
+			skipped = append(skipped, fmt.Errorf("%d decimal places for %q is outside 0 to %d", value, name, MaxPrecision))
+			continue
+		}
+		precision[units[0]] = value
+	}
+
+	return precision, skipped
+}
+
+// Round rounds value to the decimal places of unit, clamped to 0 to
+// MaxPrecision.
+func (p Precision) Round(unit Unit, value float64) float64 {
+	places, ok := p[unit]
+	if !ok {
+		places = defaultPrecision
+	}
+	places = min(max(places, 0), MaxPrecision)
+
+	scale := math.Pow10(places)
+	return math.Round(value*scale) / scale
+}
+
 type Temperature struct {
 	fahrenheit float64
 	celsius    float64
 	kelvin     float64
+	rankine    float64
+	reaumur    float64
+	condition  string
+	updatedAt  time.Time
 }
 
 func NewTemperature(celsius float64) *Temperature {
@@ -13,12 +134,45 @@ func NewTemperature(celsius float64) *Temperature {
 	return t
 }
 
+// WithCondition sets the weather condition text, e.g. "Partly cloudy", in the
+// language it was requested in.
+func (t *Temperature) WithCondition(condition string) *Temperature {
+	t.condition = condition
+	return t
+}
+
+func (t *Temperature) Condition() string {
+	return t.condition
+}
+
+// WithUpdatedAt sets when the provider last refreshed the reading.
+func (t *Temperature) WithUpdatedAt(updatedAt time.Time) *Temperature {
+	t.updatedAt = updatedAt
+	return t
+}
+
+// UpdatedAt is when the provider last refreshed the reading, zero when it
+// didn't say.
+func (t *Temperature) UpdatedAt() time.Time {
+	return t.updatedAt
+}
+
+// The conversions multiply before dividing so whole and decimal inputs don't
+// pick up the representation error of factors like 1.8.
 func (t *Temperature) convertFahrenheit() {
-	t.fahrenheit = t.celsius*1.8 + 32.0
+	t.fahrenheit = t.celsius*9/5 + 32
 }
 
 func (t *Temperature) convertKelvin() {
-	t.kelvin = t.celsius + 273.0
+	t.kelvin = t.celsius + absoluteZeroCelsius
+}
+
+func (t *Temperature) convertRankine() {
+	t.rankine = (t.celsius + absoluteZeroCelsius) * 9 / 5
+}
+
+func (t *Temperature) convertReaumur() {
+	t.reaumur = t.celsius * 4 / 5
 }
 
 func (t *Temperature) Fahrenheit() float64 {
@@ -33,7 +187,33 @@ func (t *Temperature) Celsius() float64 {
 	return t.celsius
 }
 
+func (t *Temperature) Rankine() float64 {
+	return t.rankine
+}
+
+func (t *Temperature) Reaumur() float64 {
+	return t.reaumur
+}
+
+// In returns the temperature in the given unit.
+func (t *Temperature) In(unit Unit) float64 {
+	switch unit {
+	case Fahrenheit:
+		return t.fahrenheit
+	case Kelvin:
+		return t.kelvin
+	case Rankine:
+		return t.rankine
+	case Reaumur:
+		return t.reaumur
+	default:
+		return t.celsius
+	}
+}
+
 func (t *Temperature) convert() {
 	t.convertFahrenheit()
 	t.convertKelvin()
+	t.convertRankine()
+	t.convertReaumur()
 }
@@ -9,7 +9,7 @@ import (
 func TestNewTemperature(t *testing.T) {
 	celsiusInput := 0.0
 	expectedFahrenheit := 32.0
-	expectedKelvin := 273.0
+	expectedKelvin := 273.15
 
 	temp := NewTemperature(celsiusInput)
 
@@ -23,18 +23,73 @@ func TestTemperatureConversions(t *testing.T) {
 		celsius            float64
 		expectedFahrenheit float64
 		expectedKelvin     float64
+		expectedRankine    float64
+		expectedReaumur    float64
 	}{
-		{0, 32, 273},
-		{100, 212, 373},
-		{-40, -40, 233},
+		{0, 32, 273.15, 491.67, 0},
+		{100, 212, 373.15, 671.67, 80},
+		{-40, -40, 233.15, 419.67, -32},
+		{22, 71.6, 295.15, 531.27, 17.6},
+		{-273.15, -459.67, 0, 0, -218.52},
 	}
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
 			temp := NewTemperature(tt.celsius)
-			assert.Equal(t, tt.expectedFahrenheit, temp.Fahrenheit(), "Fahrenheit conversion did not match expected value")
-			assert.Equal(t, tt.expectedKelvin, temp.Kelvin(), "Kelvin conversion did not match expected value")
+			assert.InDelta(t, tt.expectedFahrenheit, temp.Fahrenheit(), 1e-9, "Fahrenheit conversion did not match expected value")
+			assert.InDelta(t, tt.expectedKelvin, temp.Kelvin(), 1e-9, "Kelvin conversion did not match expected value")
+			assert.InDelta(t, tt.expectedRankine, temp.Rankine(), 1e-9, "Rankine conversion did not match expected value")
+			assert.InDelta(t, tt.expectedReaumur, temp.Reaumur(), 1e-9, "Réaumur conversion did not match expected value")
 			assert.Equal(t, tt.celsius, temp.Celsius(), "Celsius value should be equal to the input")
+			assert.Equal(t, temp.Rankine(), temp.In(Rankine))
 		})
 	}
 }
+
+func TestPrecisionRound(t *testing.T) {
+	precision := Precision{Celsius: 0, Kelvin: 3}
+	temp := NewTemperature(22)
+
+	assert.Equal(t, 71.6, precision.Round(Fahrenheit, temp.Fahrenheit()))
+	assert.Equal(t, 295.15, precision.Round(Kelvin, temp.Kelvin()))
+	assert.Equal(t, 23.0, precision.Round(Celsius, 22.5))
+	assert.Equal(t, 65.3, Precision(nil).Round(Fahrenheit, NewTemperature(18.5).Fahrenheit()))
+}
+
+func TestPrecisionFromNames(t *testing.T) {
+	precision, skipped := PrecisionFromNames(map[string]int{"C": 1, "kelvin": 3, "X": 4, "C,F": 2, "F": 400, "R": -1})
+
+	assert.Equal(t, Precision{Celsius: 1, Kelvin: 3}, precision)
+	var messages []string
+	for _, err := range skipped {
+		messages = append(messages, err.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		`unknown unit "X"`,
+		`unknown unit "C,F"`,
+		`400 decimal places for "F" is outside 0 to 6`,
+		`-1 decimal places for "R" is outside 0 to 6`,
+	}, messages)
+}
+
+func TestPrecisionRoundClamps(t *testing.T) {
+	precision := Precision{Celsius: 400, Kelvin: -3}
+
+	assert.Equal(t, 1e300, precision.Round(Celsius, 1e300), "must not overflow to NaN or Inf")
+	assert.Equal(t, 22.123457, precision.Round(Celsius, 22.1234567))
+	assert.Equal(t, 295.0, precision.Round(Kelvin, 295.15))
+}
+
+func TestParseUnits(t *testing.T) {
+	units, err := ParseUnits("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultUnits, units)
+
+	units, err = ParseUnits(" f, Kelvin,réaumur,R,F ")
+	assert.NoError(t, err)
+	assert.Equal(t, []Unit{Fahrenheit, Kelvin, Reaumur, Rankine}, units)
+	assert.Equal(t, "F,K,Re,R", JoinUnits(units))
+
+	_, err = ParseUnits("C,X")
+	assert.ErrorIs(t, err, ErrUnitNotValid)
+}
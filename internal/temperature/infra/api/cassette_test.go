@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	"github.com/MatheusBenetti/opentelemetry/internal/cassette"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The cassettes under testdata/cassettes hold ViaCEP and weatherapi.com
+// payloads. Re-record them against the live APIs with
+//
+//	CASSETTE_MODE=record WEATHER_API_KEY=<key> go test ./internal/temperature/infra/api -run Cassette
+//
+// liveKey is the weatherapi.com key to record with, or a placeholder when
+// replaying.
+func liveKey(t *testing.T, rec *cassette.Cassette) KeySource {
+	t.Helper()
+
+	key := config.Secret("replay-key")
+	if rec.Mode() == cassette.ModeRecord {
+		key = config.Secret(os.Getenv("WEATHER_API_KEY"))
+		require.NotEmpty(t, key, "recording needs WEATHER_API_KEY")
+	}
+
+	return StaticKey(key)
+}
+
+func TestCEPFromAPICassette(t *testing.T) {
+	rec := cassette.ForTest(t, "testdata/cassettes/viacep.json")
+	repo := NewCEPFromAPI("https://viacep.com.br", WithRoundTripper(rec))
+
+	location, err := repo.Get(context.Background(), "95670084")
+	require.NoError(t, err)
+	assert.Equal(t, entity.Location{Cep: "95670-084", Localidade: "Gramado"}, location)
+
+	_, err = repo.Get(context.Background(), "99999999")
+	assert.ErrorIs(t, err, entity.ErrCEPNotFound)
+
+	_, err = repo.Get(context.Background(), "9567008")
+	assert.ErrorIs(t, err, entity.ErrCEPProviderBadRequest)
+}
+
+func TestWeatherFromAPICassette(t *testing.T) {
+	rec := cassette.ForTest(t, "testdata/cassettes/weatherapi.json")
+	repo := NewWeatherFromAPI("https://api.weatherapi.com", liveKey(t, rec), WithRoundTripper(rec))
+
+	temperature, err := repo.Get(context.Background(), "Gramado", "pt")
+	require.NoError(t, err)
+	assert.Equal(t, 13.2, temperature.Celsius())
+	assert.Equal(t, "Parcialmente nublado", temperature.Condition())
+	assert.Equal(t, time.Unix(1715345700, 0).UTC(), temperature.UpdatedAt())
+
+	_, err = repo.Get(context.Background(), "Atlantis", entity.DefaultLanguage)
+	assert.ErrorIs(t, err, entity.ErrWeatherLocationNotFound)
+}
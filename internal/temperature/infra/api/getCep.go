@@ -2,79 +2,84 @@ package api
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
-	"time"
 
-	"github.com/MatheusBenetti/opentelemetry/config"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/MatheusBenetti/opentelemetry/internal/temperature/dto"
 	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
 )
 
 var createCepEndpoint = func(baseUrl, cep string) string {
-	return strings.Join([]string{baseUrl, "ws", cep, "json"}, "/")
+	return strings.Join([]string{strings.TrimSuffix(baseUrl, "/"), "ws", cep, "json"}, "/")
 }
 
 type CEPFromAPI struct {
-	config *config.Config
+	baseURL string
+	opts    options
 }
 
-func NewCEPFromAPI(config *config.Config) *CEPFromAPI {
+// NewCEPFromAPI looks CEPs up on the ViaCEP API at baseURL.
+func NewCEPFromAPI(baseURL string, opts ...Option) *CEPFromAPI {
 	return &CEPFromAPI{
-		config: config,
+		baseURL: baseURL,
+		opts:    newOptions(opts),
 	}
 }
 
 func (cap *CEPFromAPI) Get(ctx context.Context, cep string) (entity.Location, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, cap.opts.timeout)
 	hCtx := otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier{})
-	tracer := otel.Tracer("serviceBGetCEP")
-	_, span := tracer.Start(hCtx, "service_b:get_CEP")
-	defer span.End()
+	tracer := cap.opts.tracer("serviceBGetCEP")
+	_, span := tracer.Start(hCtx, "service_b:get_CEP", trace.WithTimestamp(cap.opts.now()))
+	defer func() {
+		span.End(trace.WithTimestamp(cap.opts.now()))
+	}()
 	defer cancel()
 
 	req, reqErr := http.NewRequestWithContext(
 		ctx,
 		http.MethodGet,
-		createCepEndpoint(cap.config.CEP.URL, cep),
+		createCepEndpoint(cap.baseURL, cep),
 		nil,
 	)
 	if reqErr != nil {
 		return entity.Location{}, reqErr
 	}
 
-	client := &http.Client{
-		Timeout: time.Second * 10,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-	resp, doErr := client.Do(req)
+	resp, doErr := cap.opts.client.Do(req)
 	if doErr != nil {
-		return entity.Location{}, doErr
+		return entity.Location{}, cepFailure(span, fmt.Errorf("%w: %w", entity.ErrCEPProviderUnavailable, doErr))
 	}
 	defer resp.Body.Close()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
 
 	bodyBytes, readErr := io.ReadAll(resp.Body)
 	if readErr != nil {
-		return entity.Location{}, readErr
+		return entity.Location{}, cepFailure(span, fmt.Errorf("%w: %w", entity.ErrCEPProviderUnavailable, readErr))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return entity.Location{}, cepFailure(span, viaCEPError(resp.StatusCode))
 	}
 
 	var location dto.LocationOut
 	if unmErr := json.Unmarshal(bodyBytes, &location); unmErr != nil {
-		return entity.Location{}, unmErr
+		return entity.Location{}, cepFailure(span, fmt.Errorf("%w: unexpected response: %w", entity.ErrCEPProviderUnavailable, unmErr))
 	}
 
-	if location.CEP == "" {
-		return entity.Location{}, entity.ErrCEPNotFound
+	if location.NotFound() || location.CEP == "" {
+		return entity.Location{}, cepFailure(span, entity.ErrCEPNotFound)
 	}
 
 	return entity.Location{
@@ -82,3 +87,39 @@ func (cap *CEPFromAPI) Get(ctx context.Context, cep string) (entity.Location, er
 		Localidade: location.Localidade,
 	}, nil
 }
+
+// viaCEPError classifies a non-200 answer from ViaCEP, which replies to bad
+// CEPs with 400 and serves HTML error pages when it is down or throttling.
+func viaCEPError(status int) error {
+	switch {
+	case status == http.StatusBadRequest:
+		return fmt.Errorf("%w: status %d", entity.ErrCEPProviderBadRequest, status)
+	case status == http.StatusNotFound:
+		return entity.ErrCEPNotFound
+	case status == http.StatusTooManyRequests, status == http.StatusForbidden:
+		return fmt.Errorf("%w: status %d", entity.ErrCEPProviderRateLimited, status)
+	default:
+		return fmt.Errorf("%w: status %d", entity.ErrCEPProviderUnavailable, status)
+	}
+}
+
+// cepFailure records why the lookup failed on the span.
+func cepFailure(span trace.Span, err error) error {
+	reason := "unavailable"
+	switch {
+	case errors.Is(err, entity.ErrCEPNotFound):
+		reason = "not_found"
+	case errors.Is(err, entity.ErrCEPProviderBadRequest):
+		reason = "bad_request"
+	case errors.Is(err, entity.ErrCEPProviderRateLimited):
+		reason = "rate_limited"
+	}
+
+	span.SetAttributes(attribute.String("cep.lookup.failure", reason))
+	if reason != "not_found" {
+		span.RecordError(err)
+	}
+	span.SetStatus(codes.Error, err.Error())
+
+	return err
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const viaCEPFound = `{
+  "cep": "95670-084",
+  "logradouro": "Rua Garibaldi",
+  "complemento": "",
+  "bairro": "Centro",
+  "localidade": "Gramado",
+  "uf": "RS",
+  "ibge": "4309100",
+  "gia": "",
+  "ddd": "54",
+  "siafi": "8681"
+}`
+
+const viaCEPErrorPage = `<!DOCTYPE html><html><body><h1>Erro</h1></body></html>`
+
+func upstream(t *testing.T, status int, body string, delay time.Duration) (*httptest.Server, <-chan *url.URL) {
+	t.Helper()
+
+	received := make(chan *url.URL, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, received
+}
+
+func TestCEPFromAPIGet(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		delay    time.Duration
+		expected entity.Location
+		errIs    error
+	}{
+		{
+			name:     "found",
+			status:   http.StatusOK,
+			body:     viaCEPFound,
+			expected: entity.Location{Cep: "95670-084", Localidade: "Gramado"},
+		},
+		{
+			name:   "not found",
+			status: http.StatusOK,
+			body:   `{"erro": true}`,
+			errIs:  entity.ErrCEPNotFound,
+		},
+		{
+			name:   "not found as string",
+			status: http.StatusOK,
+			body:   `{"erro": "true"}`,
+			errIs:  entity.ErrCEPNotFound,
+		},
+		{
+			name:   "bad request",
+			status: http.StatusBadRequest,
+			body:   viaCEPErrorPage,
+			errIs:  entity.ErrCEPProviderBadRequest,
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			body:   viaCEPErrorPage,
+			errIs:  entity.ErrCEPProviderRateLimited,
+		},
+		{
+			name:   "server error",
+			status: http.StatusBadGateway,
+			body:   viaCEPErrorPage,
+			errIs:  entity.ErrCEPProviderUnavailable,
+		},
+		{
+			name:   "HTML with status 200",
+			status: http.StatusOK,
+			body:   viaCEPErrorPage,
+			errIs:  entity.ErrCEPProviderUnavailable,
+		},
+		{
+			name:   "malformed JSON",
+			status: http.StatusOK,
+			body:   `{"cep": "95670-084",`,
+			errIs:  entity.ErrCEPProviderUnavailable,
+		},
+		{
+			name:   "timeout",
+			status: http.StatusOK,
+			body:   viaCEPFound,
+			delay:  time.Second,
+			errIs:  entity.ErrCEPProviderUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received := upstream(t, tt.status, tt.body, tt.delay)
+			repo := NewCEPFromAPI(server.URL, WithTimeout(100*time.Millisecond))
+
+			location, err := repo.Get(context.Background(), "95670084")
+			assert.Equal(t, "/ws/95670084/json", (<-received).Path)
+			if tt.errIs != nil {
+				assert.ErrorIs(t, err, tt.errIs)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, location)
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestCEPFromAPIWithRoundTripperAndClock(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	var requested string
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requested = r.URL.String()
+		rec := httptest.NewRecorder()
+		_, _ = rec.WriteString(viaCEPFound)
+		return rec.Result(), nil
+	})
+	fixed := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := NewCEPFromAPI(
+		"https://viacep.com.br",
+		WithRoundTripper(transport),
+		WithClock(func() time.Time { return fixed }),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+	location, err := repo.Get(context.Background(), "95670084")
+	require.NoError(t, err)
+	assert.Equal(t, "Gramado", location.Localidade)
+	assert.Equal(t, "https://viacep.com.br/ws/95670084/json", requested)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "service_b:get_CEP", spans[0].Name())
+	assert.Equal(t, fixed, spans[0].StartTime())
+	assert.Equal(t, fixed, spans[0].EndTime())
+}
+
+func TestCEPFromAPIRecordsFailureReason(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		reason string
+	}{
+		{status: http.StatusOK, body: `{"erro": true}`, reason: "not_found"},
+		{status: http.StatusBadRequest, body: viaCEPErrorPage, reason: "bad_request"},
+		{status: http.StatusTooManyRequests, body: viaCEPErrorPage, reason: "rate_limited"},
+		{status: http.StatusServiceUnavailable, body: viaCEPErrorPage, reason: "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			server, _ := upstream(t, tt.status, tt.body, 0)
+			repo := NewCEPFromAPI(
+				server.URL,
+				WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+			)
+			_, err := repo.Get(context.Background(), "95670084")
+			require.Error(t, err)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, codes.Error, spans[0].Status().Code)
+			assert.Contains(t, spans[0].Attributes(), attribute.String("cep.lookup.failure", tt.reason))
+			assert.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", tt.status))
+		})
+	}
+}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,66 +12,89 @@ import (
 
 	"github.com/MatheusBenetti/opentelemetry/config"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/MatheusBenetti/opentelemetry/internal/temperature/dto"
 	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
 )
 
 var createWeatherEndpoint = func(baseUrl string) string {
-	return strings.Join([]string{baseUrl, "v1", "current.json"}, "/")
+	return strings.Join([]string{strings.TrimSuffix(baseUrl, "/"), "v1", "current.json"}, "/")
+}
+
+// KeySource returns the weatherapi.com key for each request, so a rotated key
+// is picked up without rebuilding the repository.
+type KeySource func() config.Secret
+
+// StaticKey is a KeySource that always returns key.
+func StaticKey(key config.Secret) KeySource {
+	return func() config.Secret { return key }
 }
 
 type WeatherFromAPI struct {
-	config *config.Config
+	baseURL string
+	apiKey  KeySource
+	opts    options
 }
 
-func NewWeatherFromAPI(config *config.Config) *WeatherFromAPI {
+// NewWeatherFromAPI looks temperatures up on the weatherapi.com API at
+// baseURL, authenticating with the key returned by apiKey. Without a key
+// source every lookup fails with entity.ErrEmptyAPIkey.
+func NewWeatherFromAPI(baseURL string, apiKey KeySource, opts ...Option) *WeatherFromAPI {
+	if apiKey == nil {
+		apiKey = StaticKey("")
+	}
+
 	return &WeatherFromAPI{
-		config: config,
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		opts:    newOptions(opts),
 	}
 }
 
-func (wap *WeatherFromAPI) Get(ctx context.Context, location string) (entity.Temperature, error) {
+func (wap *WeatherFromAPI) Get(ctx context.Context, location string, lang entity.Language) (entity.Temperature, error) {
 	hCtx := otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier{})
-	tracer := otel.Tracer("serviceBGetWeather")
-	_, span := tracer.Start(hCtx, "service_b:get_weather")
-	defer span.End()
+	tracer := wap.opts.tracer("serviceBGetWeather")
+	_, span := tracer.Start(hCtx, "service_b:get_weather", trace.WithTimestamp(wap.opts.now()))
+	defer func() {
+		span.End(trace.WithTimestamp(wap.opts.now()))
+	}()
 
-	u, urlErr := url.Parse(createWeatherEndpoint(wap.config.Temperature.URL))
+	u, urlErr := url.Parse(createWeatherEndpoint(wap.baseURL))
 	if urlErr != nil {
 		fmt.Printf("Error parsing URL: %s\n", urlErr)
 		return entity.Temperature{}, urlErr
 	}
-	apiKey := wap.config.Temperature.ApiKey
+	apiKey := wap.apiKey()
 	if apiKey == "" {
 		return entity.Temperature{}, entity.ErrEmptyAPIkey
 	}
 
 	q := u.Query()
-	q.Set("key", wap.config.Temperature.ApiKey)
+	q.Set("key", apiKey.Reveal())
 	q.Set("q", location)
 	q.Set("aqi", "no")
+	if code := weatherAPILanguage(lang); code != "" {
+		q.Set("lang", code)
+	}
 	u.RawQuery = q.Encode()
+	span.SetAttributes(semconv.URLFull(apiKey.Redact(u.String())))
 
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, wap.opts.timeout)
 	defer cancel()
 	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if reqErr != nil {
+		reqErr = apiKey.RedactError(reqErr)
 		fmt.Printf("Error creating request: %s\n", reqErr)
-		return entity.Temperature{}, urlErr
+		return entity.Temperature{}, reqErr
 	}
 
-	client := &http.Client{
-		Timeout: time.Second * 10,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-	resp, doErr := client.Do(req)
+	resp, doErr := wap.opts.client.Do(req)
 	if doErr != nil {
+		doErr = apiKey.RedactError(doErr)
 		fmt.Printf("Error making GET request: %s\n", doErr)
 		return entity.Temperature{}, doErr
 	}
@@ -84,11 +106,71 @@ func (wap *WeatherFromAPI) Get(ctx context.Context, location string) (entity.Tem
 		return entity.Temperature{}, readErr
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		apiErr := weatherAPIError(resp.StatusCode, bodyBytes)
+		fmt.Printf("Weather API error: %s\n", apiErr)
+		span.RecordError(apiErr)
+		span.SetStatus(codes.Error, apiErr.Error())
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+		return entity.Temperature{}, apiErr
+	}
+
 	var weatherData dto.TemperatureResponseOut
 	if unmErr := json.Unmarshal(bodyBytes, &weatherData); unmErr != nil {
 		fmt.Printf("Error parsing JSON: %s\n", unmErr)
 		return entity.Temperature{}, unmErr
 	}
 
-	return *entity.NewTemperature(weatherData.Current.TempC), nil
+	temperature := entity.NewTemperature(weatherData.Current.TempC).WithCondition(weatherData.Current.Condition.Text)
+	if weatherData.Current.LastUpdatedEpoch > 0 {
+		temperature.WithUpdatedAt(time.Unix(int64(weatherData.Current.LastUpdatedEpoch), 0).UTC())
+	}
+
+	return *temperature, nil
+}
+
+// weatherAPIError maps weatherapi.com's error codes to entity errors, falling
+// back to the HTTP status when the body isn't the documented error envelope.
+// See https://www.weatherapi.com/docs/#intro-error-codes.
+func weatherAPIError(status int, body []byte) error {
+	var envelope dto.TemperatureErrorOut
+	if unmErr := json.Unmarshal(body, &envelope); unmErr == nil && envelope.Error.Code != 0 {
+		var kind error
+		switch envelope.Error.Code {
+		case 1006:
+			kind = entity.ErrWeatherLocationNotFound
+		case 1002, 2006, 2008, 2009:
+			kind = entity.ErrWeatherAPIKeyInvalid
+		case 2007:
+			kind = entity.ErrWeatherQuotaExceeded
+		default:
+			kind = entity.ErrWeatherUnavailable
+		}
+
+		return fmt.Errorf("%w: code %d: %s", kind, envelope.Error.Code, envelope.Error.Message)
+	}
+
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: status %d", entity.ErrWeatherAPIKeyInvalid, status)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: status %d", entity.ErrWeatherQuotaExceeded, status)
+	default:
+		return fmt.Errorf("%w: status %d", entity.ErrWeatherUnavailable, status)
+	}
+}
+
+// weatherAPILanguage maps a language to weatherapi.com's lang parameter.
+// English is the API's default and needs no parameter.
+func weatherAPILanguage(lang entity.Language) string {
+	switch lang {
+	case "", entity.DefaultLanguage:
+		return ""
+	case "zh-Hans":
+		return "zh"
+	case "zh-Hant":
+		return "zh_tw"
+	default:
+		return string(lang)
+	}
 }
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const weatherFound = `{
+  "location": {"name": "Gramado", "region": "Rio Grande do Sul", "country": "Brazil"},
+  "current": {
+    "last_updated_epoch": 1715342400,
+    "last_updated": "2024-05-10 09:00",
+    "temp_c": 18.5,
+    "temp_f": 65.3,
+    "condition": {"text": "Parcialmente nublado", "code": 1003}
+  }
+}`
+
+func TestWeatherFromAPIGet(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		delay     time.Duration
+		celsius   float64
+		condition string
+		wantErr   bool
+		errIs     error
+	}{
+		{
+			name:      "current weather",
+			status:    http.StatusOK,
+			body:      weatherFound,
+			celsius:   18.5,
+			condition: "Parcialmente nublado",
+		},
+		{
+			name:    "malformed JSON",
+			status:  http.StatusOK,
+			body:    `{"current": {"temp_c": `,
+			wantErr: true,
+		},
+		{
+			name:    "invalid key",
+			status:  http.StatusUnauthorized,
+			body:    `{"error": {"code": 2006, "message": "API key is invalid."}}`,
+			wantErr: true,
+			errIs:   entity.ErrWeatherAPIKeyInvalid,
+		},
+		{
+			name:    "quota exceeded",
+			status:  http.StatusForbidden,
+			body:    `{"error": {"code": 2007, "message": "API key has exceeded calls per month quota."}}`,
+			wantErr: true,
+			errIs:   entity.ErrWeatherQuotaExceeded,
+		},
+		{
+			name:    "key disabled",
+			status:  http.StatusForbidden,
+			body:    `{"error": {"code": 2008, "message": "API key has been disabled."}}`,
+			wantErr: true,
+			errIs:   entity.ErrWeatherAPIKeyInvalid,
+		},
+		{
+			name:    "no matching location",
+			status:  http.StatusBadRequest,
+			body:    `{"error": {"code": 1006, "message": "No matching location found."}}`,
+			wantErr: true,
+			errIs:   entity.ErrWeatherLocationNotFound,
+		},
+		{
+			name:    "internal application error",
+			status:  http.StatusBadRequest,
+			body:    `{"error": {"code": 9999, "message": "Internal application error."}}`,
+			wantErr: true,
+			errIs:   entity.ErrWeatherUnavailable,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusInternalServerError,
+			body:    `<html>oops</html>`,
+			wantErr: true,
+			errIs:   entity.ErrWeatherUnavailable,
+		},
+		{
+			name:    "rate limited without envelope",
+			status:  http.StatusTooManyRequests,
+			body:    ``,
+			wantErr: true,
+			errIs:   entity.ErrWeatherQuotaExceeded,
+		},
+		{
+			name:    "timeout",
+			status:  http.StatusOK,
+			body:    weatherFound,
+			delay:   time.Second,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received := upstream(t, tt.status, tt.body, tt.delay)
+			repo := NewWeatherFromAPI(server.URL, StaticKey("test-key"), WithTimeout(100*time.Millisecond))
+
+			temperature, err := repo.Get(context.Background(), "Gramado", "pt")
+			requested := <-received
+			assert.Equal(t, "/v1/current.json", requested.Path)
+			assert.Equal(t, "test-key", requested.Query().Get("key"))
+			assert.Equal(t, "Gramado", requested.Query().Get("q"))
+			assert.Equal(t, "pt", requested.Query().Get("lang"))
+
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.errIs != nil {
+					assert.ErrorIs(t, err, tt.errIs)
+				}
+				assert.NotContains(t, err.Error(), "test-key", "errors must not leak the API key")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.celsius, temperature.Celsius())
+			assert.Equal(t, tt.condition, temperature.Condition())
+		})
+	}
+}
+
+func TestWeatherFromAPIEmptyKey(t *testing.T) {
+	repo := NewWeatherFromAPI("http://unused.invalid", nil)
+
+	_, err := repo.Get(context.Background(), "Gramado", entity.DefaultLanguage)
+	assert.ErrorIs(t, err, entity.ErrEmptyAPIkey)
+}
+
+func TestWeatherFromAPIRedactsTransportErrors(t *testing.T) {
+	repo := NewWeatherFromAPI("http://127.0.0.1:1", StaticKey("test-key"))
+
+	_, err := repo.Get(context.Background(), "Gramado", entity.DefaultLanguage)
+	require.Error(t, err)
+	assert.False(t, strings.Contains(err.Error(), "test-key"))
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultTimeout bounds each upstream request, unless WithTimeout says
+// otherwise.
+const defaultTimeout = 10 * time.Second
+
+type options struct {
+	client         *http.Client
+	timeout        time.Duration
+	now            func() time.Time
+	tracerProvider trace.TracerProvider
+}
+
+// Option customises how the repositories reach their upstream API.
+type Option func(*options)
+
+// WithHTTPClient replaces the default client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithRoundTripper keeps the default client settings but sends requests
+// through rt.
+func WithRoundTripper(rt http.RoundTripper) Option {
+	return func(o *options) {
+		client := *o.client
+		client.Transport = rt
+		o.client = &client
+	}
+}
+
+// WithTimeout bounds each upstream request, 10 seconds by default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithClock sets the clock used to timestamp spans.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithTracerProvider records the repositories' spans with provider instead of
+// the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = provider
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+				},
+			},
+		},
+		timeout: defaultTimeout,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func (o options) tracer(name string) trace.Tracer {
+	if o.tracerProvider != nil {
+		return o.tracerProvider.Tracer(name)
+	}
+
+	return otel.Tracer(name)
+}
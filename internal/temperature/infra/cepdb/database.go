@@ -0,0 +1,140 @@
+package cepdb
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed ranges.csv
+var embeddedRanges []byte
+
+// Range is an inclusive range of CEPs. City is empty for ranges covering a
+// whole state.
+type Range struct {
+	Start int
+	End   int
+	UF    string
+	City  string
+}
+
+func (r Range) contains(cep int) bool {
+	return cep >= r.Start && cep <= r.End
+}
+
+// Database answers which state, and when known which city, a CEP belongs to
+// without calling any remote provider.
+type Database struct {
+	states []Range
+	cities []Range
+}
+
+// Embedded returns the database shipped with the binary.
+func Embedded() (*Database, error) {
+	return Load(bytes.NewReader(embeddedRanges))
+}
+
+func LoadFile(path string) (*Database, error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer file.Close()
+
+	return Load(file)
+}
+
+// Open loads the CEP ranges from path, falling back to the embedded database
+// when path is empty.
+func Open(path string) (*Database, error) {
+	if path == "" {
+		return Embedded()
+	}
+
+	return LoadFile(path)
+}
+
+// Load reads a CSV with the header start,end,uf,city.
+func Load(r io.Reader) (*Database, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+
+	records, readErr := reader.ReadAll()
+	if readErr != nil {
+		return nil, readErr
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty CEP range file")
+	}
+
+	db := &Database{}
+	for i, record := range records[1:] {
+		start, startErr := strconv.Atoi(record[0])
+		end, endErr := strconv.Atoi(record[1])
+		if startErr != nil || endErr != nil || start > end {
+			return nil, fmt.Errorf("invalid CEP range on line %d", i+2)
+		}
+
+		rng := Range{
+			Start: start,
+			End:   end,
+			UF:    strings.TrimSpace(record[2]),
+			City:  strings.TrimSpace(record[3]),
+		}
+		if rng.City == "" {
+			db.states = append(db.states, rng)
+		} else {
+			db.cities = append(db.cities, rng)
+		}
+	}
+
+	sortRanges(db.states)
+	sortRanges(db.cities)
+
+	return db, nil
+}
+
+func sortRanges(ranges []Range) {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+}
+
+func find(ranges []Range, cep int) (Range, bool) {
+	i := sort.Search(len(ranges), func(i int) bool {
+		return ranges[i].Start > cep
+	})
+	if i == 0 || !ranges[i-1].contains(cep) {
+		return Range{}, false
+	}
+
+	return ranges[i-1], true
+}
+
+// Lookup returns the most specific range holding cep: its city when known,
+// otherwise its state.
+func (db *Database) Lookup(cep string) (Range, bool) {
+	value, convErr := strconv.Atoi(cep)
+	if convErr != nil {
+		return Range{}, false
+	}
+
+	if city, ok := find(db.cities, value); ok {
+		return city, true
+	}
+
+	return find(db.states, value)
+}
+
+// Exists reports whether cep falls into any known state range.
+func (db *Database) Exists(cep string) bool {
+	_, ok := db.Lookup(cep)
+	return ok
+}
@@ -0,0 +1,82 @@
+package cepdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedLookup(t *testing.T) {
+	db, err := Embedded()
+	require.NoError(t, err)
+
+	tests := []struct {
+		cep   string
+		found bool
+		uf    string
+		city  string
+	}{
+		{"00000000", false, "", ""},
+		{"01001000", true, "SP", "São Paulo"},
+		{"13010000", true, "SP", ""},
+		{"95670084", true, "RS", "Gramado"},
+		{"70040010", true, "DF", "Brasília"},
+		{"99999999", true, "RS", ""},
+		{"abc", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			rng, ok := db.Lookup(tt.cep)
+			assert.Equal(t, tt.found, ok)
+			assert.Equal(t, tt.uf, rng.UF)
+			assert.Equal(t, tt.city, rng.City)
+		})
+	}
+}
+
+func TestLoadRejectsInvalidRanges(t *testing.T) {
+	_, err := Load(strings.NewReader("start,end,uf,city\n20000000,10000000,SP,\n"))
+	assert.Error(t, err)
+}
+
+type remoteFunc func(ctx context.Context, cep string) (entity.Location, error)
+
+func (f remoteFunc) Get(ctx context.Context, cep string) (entity.Location, error) {
+	return f(ctx, cep)
+}
+
+func TestLocationRepository(t *testing.T) {
+	db, err := Embedded()
+	require.NoError(t, err)
+
+	calls := 0
+	down := remoteFunc(func(context.Context, string) (entity.Location, error) {
+		calls++
+		return entity.Location{}, errors.New("connection refused")
+	})
+	notFound := remoteFunc(func(context.Context, string) (entity.Location, error) {
+		calls++
+		return entity.Location{}, entity.ErrCEPNotFound
+	})
+
+	_, err = NewLocationRepository(db, down).Get(context.Background(), "00000000")
+	assert.ErrorIs(t, err, entity.ErrCEPNotFound)
+	assert.Zero(t, calls, "unknown ranges must not reach the remote providers")
+
+	location, err := NewLocationRepository(db, down, down).Get(context.Background(), "95670084")
+	require.NoError(t, err)
+	assert.Equal(t, "Gramado", location.Localidade)
+	assert.Equal(t, 2, calls)
+
+	_, err = NewLocationRepository(db, down).Get(context.Background(), "13010000")
+	assert.EqualError(t, err, "connection refused")
+
+	_, err = NewLocationRepository(db, notFound, down).Get(context.Background(), "95670084")
+	assert.ErrorIs(t, err, entity.ErrCEPNotFound)
+}
@@ -0,0 +1,57 @@
+package cepdb
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+)
+
+// LocationRepository rejects CEPs outside every known range before any
+// remote call, then asks each remote provider in turn. A not found answer
+// from a provider is final; any other failure moves on to the next one and,
+// once all of them failed, the offline database is used.
+type LocationRepository struct {
+	db      *Database
+	remotes []entity.LocationRepository
+}
+
+func NewLocationRepository(db *Database, remotes ...entity.LocationRepository) *LocationRepository {
+	return &LocationRepository{
+		db:      db,
+		remotes: remotes,
+	}
+}
+
+func (lr *LocationRepository) Get(ctx context.Context, cep string) (entity.Location, error) {
+	rng, known := lr.db.Lookup(cep)
+	if !known {
+		return entity.Location{}, entity.ErrCEPNotFound
+	}
+
+	lastErr := entity.ErrCEPNotFound
+	for _, remote := range lr.remotes {
+		location, err := remote.Get(ctx, cep)
+		if err == nil || errors.Is(err, entity.ErrCEPNotFound) {
+			return location, err
+		}
+		lastErr = err
+	}
+
+	if rng.City == "" {
+		return entity.Location{}, lastErr
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("offline CEP lookup", trace.WithAttributes(
+		attribute.String("cep.uf", rng.UF),
+		attribute.String("cep.city", rng.City),
+	))
+
+	return entity.Location{
+		Cep:        cep,
+		Localidade: rng.City,
+	}, nil
+}
@@ -0,0 +1,180 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: temperature.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TemperatureService_GetWeather_FullMethodName    = "/temperature.v1.TemperatureService/GetWeather"
+	TemperatureService_StreamWeather_FullMethodName = "/temperature.v1.TemperatureService/StreamWeather"
+)
+
+// TemperatureServiceClient is the client API for TemperatureService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TemperatureServiceClient interface {
+	// GetWeather returns the current temperature for a single CEP.
+	GetWeather(ctx context.Context, in *GetWeatherRequest, opts ...grpc.CallOption) (*GetWeatherResponse, error)
+	// StreamWeather looks up several CEPs, streaming one result per CEP as soon
+	// as it is available.
+	StreamWeather(ctx context.Context, in *StreamWeatherRequest, opts ...grpc.CallOption) (TemperatureService_StreamWeatherClient, error)
+}
+
+type temperatureServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTemperatureServiceClient(cc grpc.ClientConnInterface) TemperatureServiceClient {
+	return &temperatureServiceClient{cc}
+}
+
+func (c *temperatureServiceClient) GetWeather(ctx context.Context, in *GetWeatherRequest, opts ...grpc.CallOption) (*GetWeatherResponse, error) {
+	out := new(GetWeatherResponse)
+	err := c.cc.Invoke(ctx, TemperatureService_GetWeather_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *temperatureServiceClient) StreamWeather(ctx context.Context, in *StreamWeatherRequest, opts ...grpc.CallOption) (TemperatureService_StreamWeatherClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TemperatureService_ServiceDesc.Streams[0], TemperatureService_StreamWeather_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &temperatureServiceStreamWeatherClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TemperatureService_StreamWeatherClient interface {
+	Recv() (*StreamWeatherResponse, error)
+	grpc.ClientStream
+}
+
+type temperatureServiceStreamWeatherClient struct {
+	grpc.ClientStream
+}
+
+func (x *temperatureServiceStreamWeatherClient) Recv() (*StreamWeatherResponse, error) {
+	m := new(StreamWeatherResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TemperatureServiceServer is the server API for TemperatureService service.
+// All implementations must embed UnimplementedTemperatureServiceServer
+// for forward compatibility
+type TemperatureServiceServer interface {
+	// GetWeather returns the current temperature for a single CEP.
+	GetWeather(context.Context, *GetWeatherRequest) (*GetWeatherResponse, error)
+	// StreamWeather looks up several CEPs, streaming one result per CEP as soon
+	// as it is available.
+	StreamWeather(*StreamWeatherRequest, TemperatureService_StreamWeatherServer) error
+	mustEmbedUnimplementedTemperatureServiceServer()
+}
+
+// UnimplementedTemperatureServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTemperatureServiceServer struct {
+}
+
+func (UnimplementedTemperatureServiceServer) GetWeather(context.Context, *GetWeatherRequest) (*GetWeatherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWeather not implemented")
+}
+func (UnimplementedTemperatureServiceServer) StreamWeather(*StreamWeatherRequest, TemperatureService_StreamWeatherServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamWeather not implemented")
+}
+func (UnimplementedTemperatureServiceServer) mustEmbedUnimplementedTemperatureServiceServer() {}
+
+// UnsafeTemperatureServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TemperatureServiceServer will
+// result in compilation errors.
+type UnsafeTemperatureServiceServer interface {
+	mustEmbedUnimplementedTemperatureServiceServer()
+}
+
+func RegisterTemperatureServiceServer(s grpc.ServiceRegistrar, srv TemperatureServiceServer) {
+	s.RegisterService(&TemperatureService_ServiceDesc, srv)
+}
+
+func _TemperatureService_GetWeather_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWeatherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemperatureServiceServer).GetWeather(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemperatureService_GetWeather_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemperatureServiceServer).GetWeather(ctx, req.(*GetWeatherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TemperatureService_StreamWeather_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamWeatherRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TemperatureServiceServer).StreamWeather(m, &temperatureServiceStreamWeatherServer{stream})
+}
+
+type TemperatureService_StreamWeatherServer interface {
+	Send(*StreamWeatherResponse) error
+	grpc.ServerStream
+}
+
+type temperatureServiceStreamWeatherServer struct {
+	grpc.ServerStream
+}
+
+func (x *temperatureServiceStreamWeatherServer) Send(m *StreamWeatherResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TemperatureService_ServiceDesc is the grpc.ServiceDesc for TemperatureService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TemperatureService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "temperature.v1.TemperatureService",
+	HandlerType: (*TemperatureServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetWeather",
+			Handler:    _TemperatureService_GetWeather_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamWeather",
+			Handler:       _TemperatureService_StreamWeather_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "temperature.proto",
+}
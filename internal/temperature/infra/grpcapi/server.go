@@ -0,0 +1,252 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"runtime/debug"
+	"time"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/dto"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/infra/grpcapi/pb"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	grpcCodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details service B sends.
+const ErrorDomain = "temperature.v1"
+
+// Limits of the gRPC server, matching the HTTP servers' body cap and idle
+// timeout. Clients may ping at most every KeepaliveMinTime.
+const (
+	MaxRecvMsgBytes   = 1 << 20
+	MaxConnectionIdle = 2 * time.Minute
+	KeepaliveTime     = 2 * time.Minute
+	KeepaliveTimeout  = 20 * time.Second
+	KeepaliveMinTime  = 30 * time.Second
+)
+
+type Server struct {
+	pb.UnimplementedTemperatureServiceServer
+	getWeather usecase.GetWeather
+	provider   trace.TracerProvider
+	tracer     trace.Tracer
+	spanName   string
+}
+
+// NewServer traces each call as spanName, and the otelgrpc spans around it,
+// with provider.
+func NewServer(getWeather usecase.GetWeather, provider trace.TracerProvider, spanName string) *Server {
+	return &Server{
+		getWeather: getWeather,
+		provider:   provider,
+		tracer:     provider.Tracer("service_b"),
+		spanName:   spanName,
+	}
+}
+
+func (s *Server) GetWeather(ctx context.Context, req *pb.GetWeatherRequest) (*pb.GetWeatherResponse, error) {
+	ctx, span := s.tracer.Start(ctx, s.spanName)
+	defer span.End()
+	recordCaller(ctx, span)
+	recordNegotiation(span, req.GetUnits(), req.GetLanguage())
+
+	out, execErr := s.getWeather.Execute(ctx, dto.LocationInput{
+		CEP:      req.GetCep(),
+		Units:    req.GetUnits(),
+		Language: req.GetLanguage(),
+	})
+	if execErr != nil {
+		span.SetStatus(codes.Error, execErr.Error())
+		return nil, statusFromError(execErr)
+	}
+
+	return toResponse(out), nil
+}
+
+func (s *Server) StreamWeather(req *pb.StreamWeatherRequest, stream pb.TemperatureService_StreamWeatherServer) error {
+	ctx, span := s.tracer.Start(stream.Context(), s.spanName)
+	defer span.End()
+	recordCaller(ctx, span)
+	recordNegotiation(span, req.GetUnits(), req.GetLanguage())
+
+	for _, cep := range req.GetCeps() {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		resp := &pb.StreamWeatherResponse{Cep: cep}
+		out, execErr := s.getWeather.Execute(ctx, dto.LocationInput{
+			CEP:      cep,
+			Units:    req.GetUnits(),
+			Language: req.GetLanguage(),
+		})
+		if execErr != nil {
+			st := status.Convert(statusFromError(execErr))
+			resp.Result = &pb.StreamWeatherResponse_Error{
+				Error: &pb.Error{Code: int32(st.Code()), Message: st.Message(), Reason: contract.ErrorCode(execErr)},
+			}
+		} else {
+			resp.Result = &pb.StreamWeatherResponse_Weather{Weather: toResponse(out)}
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Serve registers the server on an otelgrpc instrumented grpc.Server and blocks
+// serving on listener. Requests are capped at MaxRecvMsgBytes, idle
+// connections are closed and a panicking call is answered with Internal.
+func (s *Server) Serve(listener net.Listener) error {
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithTracerProvider(s.provider),
+		)),
+		grpc.ChainUnaryInterceptor(recoverUnary),
+		grpc.ChainStreamInterceptor(recoverStream),
+		grpc.MaxRecvMsgSize(MaxRecvMsgBytes),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: MaxConnectionIdle,
+			Time:              KeepaliveTime,
+			Timeout:           KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             KeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	)
+	pb.RegisterTemperatureServiceServer(grpcServer, s)
+
+	log.Println("gRPC server listening on", listener.Addr())
+	return grpcServer.Serve(listener)
+}
+
+// recoverUnary answers a panicking call with Internal instead of crashing the
+// process, like httpserver.Recover does for HTTP.
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = panicStatus(ctx, info.FullMethod, recovered)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func recoverStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = panicStatus(stream.Context(), info.FullMethod, recovered)
+		}
+	}()
+
+	return handler(srv, stream)
+}
+
+// panicStatus logs the stack trace and records the panic on the otelgrpc
+// span of the call.
+func panicStatus(ctx context.Context, method string, recovered any) error {
+	log.Printf("panic serving %s: %v\n%s", method, recovered, debug.Stack())
+
+	err := fmt.Errorf("panic: %v", recovered)
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err, trace.WithStackTrace(true))
+	span.SetStatus(codes.Error, err.Error())
+
+	return status.Error(grpcCodes.Internal, "internal error")
+}
+
+func toResponse(out dto.TemperatureOutput) *pb.GetWeatherResponse {
+	resp := &pb.GetWeatherResponse{
+		Location:  out.Location,
+		Condition: out.Condition,
+		TempC:     out.TempC,
+		TempF:     out.TempF,
+		TempK:     out.TempK,
+		TempR:     out.TempR,
+		TempRe:    out.TempRe,
+	}
+	if out.LastUpdated != nil {
+		resp.LastUpdated = timestamppb.New(*out.LastUpdated)
+	}
+
+	return resp
+}
+
+// recordCaller records the client service A says it authenticated, carried
+// in the baggage the same way as over HTTP.
+func recordCaller(ctx context.Context, span trace.Span) {
+	if clientID, ok := contract.UnverifiedClientID(ctx); ok {
+		span.SetAttributes(clientID)
+	}
+}
+
+func recordNegotiation(span trace.Span, units, lang string) {
+	if parsed, err := entity.ParseUnits(units); err == nil {
+		units = entity.JoinUnits(parsed)
+	}
+
+	span.SetAttributes(
+		attribute.String("temperature.units", units),
+		attribute.String("temperature.language", string(entity.NegotiateLanguage(lang))),
+	)
+}
+
+// CodeFor is the grpc status code service B answers err with.
+func CodeFor(err error) grpcCodes.Code {
+	switch {
+	case errors.Is(err, entity.ErrCEPNotValid),
+		errors.Is(err, entity.ErrUnitNotValid):
+		return grpcCodes.InvalidArgument
+	case errors.Is(err, entity.ErrCEPNotFound),
+		errors.Is(err, entity.ErrWeatherLocationNotFound):
+		return grpcCodes.NotFound
+	case errors.Is(err, entity.ErrCEPProviderBadRequest),
+		errors.Is(err, entity.ErrWeatherAPIKeyInvalid):
+		return grpcCodes.FailedPrecondition
+	case errors.Is(err, entity.ErrCEPProviderRateLimited),
+		errors.Is(err, entity.ErrWeatherQuotaExceeded):
+		return grpcCodes.ResourceExhausted
+	case errors.Is(err, entity.ErrCEPProviderUnavailable),
+		errors.Is(err, entity.ErrWeatherUnavailable):
+		return grpcCodes.Unavailable
+	default:
+		return grpcCodes.Internal
+	}
+}
+
+// statusFromError names err with its contract error code in an ErrorInfo
+// detail, so the client doesn't have to match the message.
+func statusFromError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return status.FromContextError(err).Err()
+	}
+
+	st := status.New(CodeFor(err), err.Error())
+	withInfo, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: contract.ErrorCode(err),
+		Domain: ErrorDomain,
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withInfo.Err()
+}
@@ -0,0 +1,57 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/httpserver"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/dto"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// temperature answers GET /temperature?cep=&units= in the negotiated
+// language, mapping use case errors to their contract status.
+func (s *Server) temperature(writer http.ResponseWriter, request *http.Request) {
+	carrier := propagation.HeaderCarrier(request.Header)
+	ctx := otel.GetTextMapPropagator().Extract(request.Context(), carrier)
+
+	ctx, span := s.OTELTracer.Start(ctx, s.RequestNameOtel)
+	defer httpserver.EndSpan(span)
+	if clientID, ok := contract.UnverifiedClientID(ctx); ok {
+		span.SetAttributes(clientID)
+	}
+
+	query := request.URL.Query()
+	lang := entity.NegotiateLanguage(request.Header.Get("Accept-Language"))
+	span.SetAttributes(
+		attribute.String("temperature.units", query.Get(contract.QueryUnits)),
+		attribute.String("temperature.language", string(lang)),
+	)
+
+	temperature, execErr := s.GetWeather.Execute(ctx, dto.LocationInput{
+		CEP:      query.Get(contract.QueryCEP),
+		Units:    query.Get(contract.QueryUnits),
+		Language: string(lang),
+	})
+	if execErr != nil {
+		contract.WriteError(writer, execErr)
+		return
+	}
+
+	jsonData, marshErr := json.Marshal(temperature)
+	if marshErr != nil {
+		http.Error(writer, "Error generating JSON", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("Content-Language", string(lang))
+	if _, wErr := writer.Write(jsonData); wErr != nil {
+		http.Error(writer, "Error writing", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/httpserver"
+	"github.com/MatheusBenetti/opentelemetry/internal/openapi"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultRequestName names the span of each request when
+// Server.RequestNameOtel is unset.
+const DefaultRequestName = "service_b:all"
+
+type Middleware func(http.Handler) http.Handler
+
+// Server serves service B's HTTP API. Requests are validated against OpenAPI
+// when it is set, and panics are answered with a 500.
+type Server struct {
+	Port            string
+	RequestNameOtel string       // DefaultRequestName when unset
+	OTELTracer      trace.Tracer // the global provider's "service_b" tracer when unset
+	GetWeather      usecase.GetWeather
+	OpenAPI         *openapi.Spec
+	Middlewares     []Middleware
+	HTTP            config.HTTPServer
+	mux             *http.ServeMux
+	handler         http.Handler
+}
+
+func (s *Server) prepare() {
+	if s.OTELTracer == nil {
+		s.OTELTracer = otel.Tracer("service_b")
+	}
+	if s.RequestNameOtel == "" {
+		s.RequestNameOtel = DefaultRequestName
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET "+contract.TemperaturePath, s.temperature)
+
+	s.handler = s.mux
+	if s.OpenAPI != nil {
+		s.mux.Handle("GET "+openapi.Path, s.OpenAPI)
+		s.handler = s.OpenAPI.Middleware(s.handler)
+	}
+	for i := len(s.Middlewares) - 1; i >= 0; i-- {
+		s.handler = s.Middlewares[i](s.handler)
+	}
+	s.handler = httpserver.Recover(s.handler)
+}
+
+func (s *Server) run() {
+	if err := httpserver.New(":"+s.Port, s.handler, s.HTTP).ListenAndServe(); err != nil {
+		log.Println("server error", err)
+		return
+	}
+}
+
+// Handler builds the server's routes and middleware chain, so it can be
+// mounted elsewhere, such as on an httptest.Server.
+func (s *Server) Handler() http.Handler {
+	s.prepare()
+	return s.handler
+}
+
+func (s *Server) Execute() {
+	s.prepare()
+	s.run()
+}
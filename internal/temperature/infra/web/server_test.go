@@ -0,0 +1,187 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/openapi"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/usecase/usecasetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func newServer(t *testing.T) *Server {
+	t.Helper()
+
+	spec, specErr := openapi.ServiceB()
+	require.NoError(t, specErr)
+
+	return &Server{
+		OTELTracer: noop.NewTracerProvider().Tracer(""),
+		GetWeather: usecase.NewGetWeather(
+			usecasetest.Locations{"95670084": "Gramado", "20040020": "Down"},
+			usecasetest.Temperatures{"Down": fmt.Errorf("%w: status 500", entity.ErrWeatherUnavailable)},
+			nil,
+		),
+		OpenAPI: spec,
+	}
+}
+
+func TestTemperature(t *testing.T) {
+	handler := newServer(t).Handler()
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		body   string
+	}{
+		{"found", "?cep=95670084&units=C,K", http.StatusOK, ""},
+		{"invalid CEP", "?cep=9567", http.StatusUnprocessableEntity, entity.ErrCEPNotValid.Error()},
+		{"unknown unit", "?cep=95670084&units=X", http.StatusBadRequest, entity.ErrUnitNotValid.Error()},
+		{"unknown CEP", "?cep=99999999", http.StatusNotFound, entity.ErrCEPNotFound.Error()},
+		{"weather down", "?cep=20040020", http.StatusServiceUnavailable, entity.ErrWeatherUnavailable.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, contract.TemperaturePath+tt.query, nil))
+
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.body != "" {
+				assert.Contains(t, rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestTemperatureNegotiatesLanguage(t *testing.T) {
+	handler := newServer(t).Handler()
+
+	req := httptest.NewRequest(http.MethodGet, contract.TemperaturePath+"?cep=95670084&units=C,F", nil)
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "pt", rec.Header().Get("Content-Language"))
+
+	var out contract.TemperatureResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+	assert.Equal(t, "Gramado", out.Location)
+	assert.Equal(t, "Ensolarado", out.Condition)
+	require.NotNil(t, out.TempC)
+	require.NotNil(t, out.TempF)
+	assert.Equal(t, usecasetest.Celsius, *out.TempC)
+	assert.Nil(t, out.TempK)
+	require.NotNil(t, out.LastUpdated)
+	assert.Equal(t, usecasetest.UpdatedAt, *out.LastUpdated)
+}
+
+// headerAfterBody fails the test when the status is written after the body,
+// which net/http would log as a superfluous WriteHeader call.
+type headerAfterBody struct {
+	*httptest.ResponseRecorder
+	t     *testing.T
+	wrote bool
+}
+
+func (w *headerAfterBody) WriteHeader(status int) {
+	if w.wrote {
+		w.t.Errorf("WriteHeader(%d) called after the body", status)
+	}
+	w.ResponseRecorder.WriteHeader(status)
+}
+
+func (w *headerAfterBody) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestTemperatureWritesStatusBeforeBody(t *testing.T) {
+	server := newServer(t)
+	server.OpenAPI = nil
+	handler := server.Handler()
+
+	for _, query := range []string{"?cep=95670084", "?cep=99999999"} {
+		rec := &headerAfterBody{ResponseRecorder: httptest.NewRecorder(), t: t}
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, contract.TemperaturePath+query, nil))
+	}
+}
+
+func TestServerTracesRequests(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	server := newServer(t)
+	server.OTELTracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	handler := server.Handler()
+
+	member, memberErr := baggage.NewMember(contract.ClientIDBaggageKey, "tempctl")
+	require.NoError(t, memberErr)
+	bag, bagErr := baggage.New(member)
+	require.NoError(t, bagErr)
+
+	req := httptest.NewRequest(http.MethodGet, contract.TemperaturePath+"?cep=95670084&units=K", nil)
+	req = req.WithContext(baggage.ContextWithBaggage(req.Context(), bag))
+	req.Header.Set("Accept-Language", "en")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, DefaultRequestName, spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), contract.ClientIDUnverifiedKey.String("tempctl"))
+	assert.NotContains(t, spans[0].Attributes(), semconv.EnduserID("tempctl"), "service B can't verify the client")
+	assert.Contains(t, spans[0].Attributes(), attribute.String("temperature.units", "K"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("temperature.language", "en"))
+}
+
+func TestServerMiddlewares(t *testing.T) {
+	var order []string
+	middleware := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	server := newServer(t)
+	server.Middlewares = []Middleware{middleware("first"), middleware("second")}
+	handler := server.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, openapi.Path, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestServerRecoversPanics(t *testing.T) {
+	previous := log.Writer()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(previous) })
+
+	server := newServer(t)
+	server.Middlewares = []Middleware{func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") })
+	}}
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, contract.TemperaturePath+"?cep=95670084", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -10,15 +10,18 @@ import (
 type GetWeather struct {
 	locationRepo entity.LocationRepository
 	tempRepo     entity.TemperatureRepository
+	precision    entity.Precision
 }
 
 func NewGetWeather(
 	locationRepo entity.LocationRepository,
 	tempRepo entity.TemperatureRepository,
+	precision entity.Precision,
 ) GetWeather {
 	return GetWeather{
 		locationRepo: locationRepo,
 		tempRepo:     tempRepo,
+		precision:    precision,
 	}
 }
 
@@ -26,24 +29,48 @@ func (gw *GetWeather) Execute(
 	ctx context.Context,
 	input dto.LocationInput,
 ) (dto.TemperatureOutput, error) {
-	if cepErr := entity.CEPValidation(input.CEP); cepErr != nil {
+	cep, cepErr := entity.ParseCEP(input.CEP)
+	if cepErr != nil {
 		return dto.TemperatureOutput{}, cepErr
 	}
 
-	location, err := gw.locationRepo.Get(ctx, input.CEP)
+	units, unitErr := entity.ParseUnits(input.Units)
+	if unitErr != nil {
+		return dto.TemperatureOutput{}, unitErr
+	}
+
+	location, err := gw.locationRepo.Get(ctx, cep.String())
 	if err != nil {
 		return dto.TemperatureOutput{}, err
 	}
 
-	temperature, err := gw.tempRepo.Get(ctx, location.Localidade)
+	temperature, err := gw.tempRepo.Get(ctx, location.Localidade, entity.NegotiateLanguage(input.Language))
 	if err != nil {
 		return dto.TemperatureOutput{}, err
 	}
 
-	return dto.TemperatureOutput{
-		Location: location.Localidade,
-		TempC:    temperature.Celsius(),
-		TempF:    temperature.Fahrenheit(),
-		TempK:    temperature.Kelvin(),
-	}, nil
+	output := dto.TemperatureOutput{
+		Location:  location.Localidade,
+		Condition: temperature.Condition(),
+	}
+	if updatedAt := temperature.UpdatedAt(); !updatedAt.IsZero() {
+		output.LastUpdated = &updatedAt
+	}
+	for _, unit := range units {
+		value := gw.precision.Round(unit, temperature.In(unit))
+		switch unit {
+		case entity.Celsius:
+			output.TempC = &value
+		case entity.Fahrenheit:
+			output.TempF = &value
+		case entity.Kelvin:
+			output.TempK = &value
+		case entity.Rankine:
+			output.TempR = &value
+		case entity.Reaumur:
+			output.TempRe = &value
+		}
+	}
+
+	return output, nil
 }
@@ -0,0 +1,53 @@
+// Package usecasetest provides in-memory repositories for the GetWeather use
+// case, for the tests of the servers and clients built on top of it.
+package usecasetest
+
+import (
+	"context"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+)
+
+const (
+	// Celsius is the temperature Temperatures reports for every city.
+	Celsius = 25.0
+
+	// Condition is the weather condition reported in English, and
+	// ConditionPT in Portuguese.
+	Condition   = "Sunny"
+	ConditionPT = "Ensolarado"
+)
+
+// UpdatedAt is when Temperatures says the readings were last refreshed.
+var UpdatedAt = time.Date(2024, 3, 10, 13, 45, 0, 0, time.UTC)
+
+// Locations maps CEPs to their city. Any other CEP is entity.ErrCEPNotFound.
+type Locations map[string]string
+
+func (l Locations) Get(_ context.Context, cep string) (entity.Location, error) {
+	city, ok := l[cep]
+	if !ok {
+		return entity.Location{}, entity.ErrCEPNotFound
+	}
+
+	return entity.Location{Cep: cep, Localidade: city}, nil
+}
+
+// Temperatures fails the lookups of the cities it maps to an error and
+// answers every other city with Celsius and Condition, in Portuguese when
+// asked.
+type Temperatures map[string]error
+
+func (t Temperatures) Get(_ context.Context, location string, lang entity.Language) (entity.Temperature, error) {
+	if err := t[location]; err != nil {
+		return entity.Temperature{}, err
+	}
+
+	condition := Condition
+	if lang == "pt" {
+		condition = ConditionPT
+	}
+
+	return *entity.NewTemperature(Celsius).WithCondition(condition).WithUpdatedAt(UpdatedAt), nil
+}
@@ -1,7 +1,15 @@
+// Command opentelemetry runs service A and service B in a single process, for
+// local deployments and demos. Each service keeps its own TracerProvider, so
+// their spans still reach Zipkin as two services.
+//
+//	go run . -link inprocess
+//	go run . -link loopback
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,121 +17,134 @@ import (
 	"os/signal"
 	"time"
 
-	"github.com/spf13/viper"
-
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/httpserver"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/opentel"
+	"github.com/MatheusBenetti/opentelemetry/internal/services"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func initProvider(serviceName, collectorURL string) (func(context.Context) error, error) {
-	ctx := context.Background()
+const (
+	// linkInProcess hands service A's requests to service B's handler
+	// without a connection.
+	linkInProcess = "inprocess"
+	// linkLoopback serves service B on its port and calls it over HTTP.
+	linkLoopback = "loopback"
+)
 
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(serviceName),
-		),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
-	}
-	ctx, cancel := context.WithTimeout(ctx, time.Second)
+func main() {
+	configFile := flag.String("config", "env.json", "config file, read from the working directory")
+	link := flag.String("link", linkInProcess, "how service A calls service B: inprocess or loopback")
+	flag.Parse()
+
+	var cfg config.Config
+	viperCfg := config.NewViper(*configFile)
+	viperCfg.ReadViper(&cfg)
+	defer viperCfg.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
-	conn, err := grpc.DialContext(ctx, collectorURL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create gRPC connection to collector: %w", err)
+
+	providerA, provAErr := opentel.NewProvider("service_a_orchestration", cfg.Zipkin.Endpoint, opentel.WithConfig(cfg.Otel))
+	if provAErr != nil {
+		log.Printf("failed creating service A's tracer provider %s\n", provAErr.Error())
+		return
+	}
+	providerB, provBErr := opentel.NewProvider("service_b", cfg.Zipkin.Endpoint, opentel.WithConfig(cfg.Otel))
+	if provBErr != nil {
+		log.Printf("failed creating service B's tracer provider %s\n", provBErr.Error())
+		return
 	}
+	meterProviderA, meterAErr := opentel.NewMeterProvider("service_a_orchestration", cfg.Otel.MetricsEndpoint, opentel.WithConfig(cfg.Otel))
+	if meterAErr != nil {
+		log.Printf("failed creating service A's meter provider %s\n", meterAErr.Error())
+		return
+	}
+	otel.SetTextMapPropagator(opentel.Propagator())
 
-	traceExporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	handlers, handlersErr := newHandlers(&cfg, *link, providerA, providerB, meterProviderA)
+	if handlersErr != nil {
+		log.Println(handlersErr)
+		return
 	}
 
-	bsp := sdktrace.NewBatchSpanProcessor(traceExporter)
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithResource(res),
-		sdktrace.WithSpanProcessor(bsp),
-	)
-	otel.SetTracerProvider(tracerProvider)
+	servers := []*http.Server{httpserver.New(":"+cfg.ServiceA.Port, handlers.serviceA, cfg.ServiceA.HTTP)}
+	if *link == linkLoopback {
+		servers = append(servers, httpserver.New(":"+cfg.ServiceB.Port, handlers.serviceB, cfg.ServiceB.HTTP))
+	}
 
-	otel.SetTextMapPropagator(propagation.TraceContext{})
+	serveErr := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			log.Printf("serving on %s\n", server.Addr)
+			serveErr <- server.ListenAndServe()
+		}()
+	}
 
-	return tracerProvider.Shutdown, nil
-}
+	select {
+	case <-ctx.Done():
+		log.Println("shutting down")
+	case err := <-serveErr:
+		log.Println("server error", err)
+	}
 
-func init() {
-	viper.AutomaticEnv()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("failed shutting down %s %s\n", server.Addr, err.Error())
+		}
+	}
+	for _, provider := range []*sdktrace.TracerProvider{providerA, providerB} {
+		if err := provider.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed shuting down the tracer provider %s\n", err.Error())
+		}
+	}
+	if err := meterProviderA.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed shuting down the meter provider %s\n", err.Error())
+	}
 }
 
-func main() {
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt)
-
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer cancel()
+// serviceHandlers holds both services' handlers. Service B's is only served on its
+// own port over the loopback link.
+type serviceHandlers struct {
+	serviceA http.Handler
+	serviceB http.Handler
+}
 
-	shutdownServiceA, err := initProvider("ServiceA", viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT_A"))
-	if err != nil {
-		log.Fatal(err)
+// newHandlers builds service B and service A as their cmd binaries do, with
+// service A reaching service B over link and each tracing through its own
+// provider. Only service A records metrics, through meterProviderA.
+func newHandlers(cfg *config.Config, link string, providerA, providerB trace.TracerProvider, meterProviderA metric.MeterProvider) (serviceHandlers, error) {
+	gw, gwErr := services.GetWeather(cfg, providerB)
+	if gwErr != nil {
+		return serviceHandlers{}, gwErr
 	}
-	defer func() {
-		if err := shutdownServiceA(ctx); err != nil {
-			log.Fatal("failed to shutdown TracerProvider for ServiceA: %w", err)
-		}
-	}()
 
-	shutdownServiceB, err := initProvider("ServiceB", viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT_B"))
-	if err != nil {
-		log.Fatal(err)
+	serverB, serverBErr := services.ServiceB(cfg, gw, providerB)
+	if serverBErr != nil {
+		return serviceHandlers{}, serverBErr
+	}
+	handlerB := serverB.Handler()
+
+	var tempClient *contract.Client
+	switch link {
+	case linkInProcess:
+		tempClient = contract.NewClient("service-b.in-process", contract.WithRoundTripper(httpserver.InProcess(handlerB)))
+	case linkLoopback:
+		tempClient = contract.NewClient("127.0.0.1:" + cfg.ServiceB.Port)
+	default:
+		return serviceHandlers{}, fmt.Errorf("unknown link %q, want %s or %s", link, linkInProcess, linkLoopback)
 	}
-	defer func() {
-		if err := shutdownServiceB(ctx); err != nil {
-			log.Fatal("failed to shutdown TracerProvider for ServiceB: %w", err)
-		}
-	}()
-
-	routerServiceA := http.NewServeMux()
-	routerServiceA.HandleFunc("/serviceA", func(w http.ResponseWriter, r *http.Request) {
-		// Your logic for Service A
-	})
-
-	routerServiceB := http.NewServeMux()
-	routerServiceB.HandleFunc("/serviceB", func(w http.ResponseWriter, r *http.Request) {
-		// Your logic for Service B
-	})
-
-	go func() {
-		log.Println("Starting Service A on port", viper.GetString("HTTP_PORT_A"))
-		if err := http.ListenAndServe(viper.GetString("HTTP_PORT_A"), routerServiceA); err != nil {
-			log.Fatal(err)
-		}
-	}()
-
-	go func() {
-		log.Println("Starting Service B on port", viper.GetString("HTTP_PORT_B"))
-		if err := http.ListenAndServe(viper.GetString("HTTP_PORT_B"), routerServiceB); err != nil {
-			log.Fatal(err)
-		}
-	}()
 
-	select {
-	case <-sigCh:
-		log.Println("Shutting down gracefully, CTRL+C pressed...")
-	case <-ctx.Done():
-		log.Println("Shutting down due to other reason...")
+	serverA, serverAErr := services.ServiceA(cfg, tempClient, providerA, meterProviderA)
+	if serverAErr != nil {
+		return serviceHandlers{}, serverAErr
 	}
 
-	// Create a timeout context for the graceful shutdown
-	_, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer shutdownCancel()
+	return serviceHandlers{serviceA: serverA.Handler(), serviceB: handlerB}, nil
 }
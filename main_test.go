@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/fakeupstream"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/opentel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func newTestProvider(t *testing.T, serviceName string) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	return provider, exporter
+}
+
+func findSpan(spans tracetest.SpanStubs, name string) (tracetest.SpanStub, bool) {
+	for _, span := range spans {
+		if span.Name == name {
+			return span, true
+		}
+	}
+	return tracetest.SpanStub{}, false
+}
+
+func TestAllInOne(t *testing.T) {
+	fixtures, fixErr := fakeupstream.EmbeddedFixtures()
+	require.NoError(t, fixErr)
+	upstreams := httptest.NewServer(fakeupstream.NewHandler(fixtures))
+	defer upstreams.Close()
+
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(opentel.Propagator())
+	t.Cleanup(func() { otel.SetTextMapPropagator(previousPropagator) })
+
+	for _, link := range []string{linkInProcess, linkLoopback} {
+		t.Run(link, func(t *testing.T) {
+			cfg := config.Config{
+				CEP:         config.CEP{URL: upstreams.URL},
+				Temperature: config.Temperature{URL: upstreams.URL, ApiKey: "fake-key"},
+			}
+
+			var listener net.Listener
+			if link == linkLoopback {
+				var listenErr error
+				listener, listenErr = net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, listenErr)
+				cfg.ServiceB.Port = strings.TrimPrefix(listener.Addr().String(), "127.0.0.1:")
+			}
+
+			providerA, exporterA := newTestProvider(t, "service_a_orchestration")
+			providerB, exporterB := newTestProvider(t, "service_b")
+
+			handlers, err := newHandlers(&cfg, link, providerA, providerB, metricnoop.NewMeterProvider())
+			require.NoError(t, err)
+			if listener != nil {
+				serverB := &http.Server{Handler: handlers.serviceB}
+				go serverB.Serve(listener)
+				defer serverB.Close()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, contract.GetCepPath, strings.NewReader(`{"cep": "95670084"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			handlers.serviceA.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), `"city":"Gramado"`)
+
+			spanA, okA := findSpan(exporterA.GetSpans(), "service_a:all")
+			require.True(t, okA)
+			assert.Contains(t, spanA.Resource.Attributes(), semconv.ServiceName("service_a_orchestration"))
+
+			var spanB tracetest.SpanStub
+			require.Eventually(t, func() bool {
+				var okB bool
+				spanB, okB = findSpan(exporterB.GetSpans(), "service_b:all")
+				return okB
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.Contains(t, spanB.Resource.Attributes(), semconv.ServiceName("service_b"))
+			assert.Equal(t, spanA.SpanContext.TraceID(), spanB.SpanContext.TraceID())
+			assert.Equal(t, spanA.SpanContext.SpanID(), spanB.Parent.SpanID())
+
+			_, leaked := findSpan(exporterA.GetSpans(), "service_b:all")
+			assert.False(t, leaked, "service B's span was exported by service A's provider")
+			_, okCEP := findSpan(exporterB.GetSpans(), "service_b:get_CEP")
+			assert.True(t, okCEP, "service B's repository spans should use service B's provider")
+		})
+	}
+}
+
+func TestAllInOneRejectsUnknownLinks(t *testing.T) {
+	provider, _ := newTestProvider(t, "test")
+
+	_, err := newHandlers(&config.Config{}, "carrier-pigeon", provider, provider, metricnoop.NewMeterProvider())
+	assert.EqualError(t, err, `unknown link "carrier-pigeon", want inprocess or loopback`)
+}
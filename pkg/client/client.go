@@ -0,0 +1,282 @@
+// Package client calls service A's POST /getCep for one or many CEPs. It
+// propagates the caller's trace context, retries throttled and unavailable
+// answers, and maps error responses to the errors of this package.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Temperature is service A's answer. Only the requested units are set.
+type Temperature struct {
+	City      string   `json:"city"`
+	Condition string   `json:"condition,omitempty"`
+	TempC     *float64 `json:"temp_C,omitempty"`
+	TempF     *float64 `json:"temp_F,omitempty"`
+	TempK     *float64 `json:"temp_K,omitempty"`
+	TempR     *float64 `json:"temp_R,omitempty"`
+	TempRe    *float64 `json:"temp_Re,omitempty"`
+
+	// LastUpdated is when the weather provider last refreshed the reading.
+	LastUpdated *time.Time `json:"last_updated,omitempty"`
+}
+
+type options struct {
+	httpClient   *http.Client
+	apiKey       string
+	apiKeyHeader string
+	retries      int
+	backoff      time.Duration
+	concurrency  int
+	propagator   propagation.TextMapPropagator
+}
+
+// Option customises a Client.
+type Option func(*options)
+
+// WithHTTPClient replaces the default client, which has a 10 second timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithAPIKey sends key in the X-API-Key header, or in header when given.
+func WithAPIKey(key string, header ...string) Option {
+	return func(o *options) {
+		o.apiKey = key
+		if len(header) > 0 {
+			o.apiKeyHeader = header[0]
+		}
+	}
+}
+
+// WithRetries sets how many times a throttled, unavailable or failed request
+// is sent again, 2 by default. Waits start at backoff and double on each
+// attempt, unless service A asks for longer with Retry-After.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = retries
+		o.backoff = backoff
+	}
+}
+
+// WithConcurrency caps how many lookups Batch runs at once, 4 by default.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithPropagator sets how the trace context is sent, the global otel
+// propagator by default.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagator = propagator
+	}
+}
+
+// Client is safe for concurrent use.
+type Client struct {
+	baseURL string
+	opts    options
+}
+
+// New returns a client for service A at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, opts ...Option) *Client {
+	o := options{
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		apiKeyHeader: "X-API-Key",
+		retries:      2,
+		backoff:      200 * time.Millisecond,
+		concurrency:  4,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		opts:    o,
+	}
+}
+
+type lookup struct {
+	units    []string
+	language string
+}
+
+// LookupOption customises a single lookup.
+type LookupOption func(*lookup)
+
+// Units picks the units to return, such as "C", "F", "K", "R" and "Re".
+// Service A returns C, F and K when none are given.
+func Units(units ...string) LookupOption {
+	return func(l *lookup) {
+		l.units = units
+	}
+}
+
+// Language asks for the weather condition in a BCP 47 language, e.g. "pt-BR".
+func Language(tag string) LookupOption {
+	return func(l *lookup) {
+		l.language = tag
+	}
+}
+
+// Temperature returns the current temperature for cep, which may be
+// formatted, e.g. 95670-084.
+func (c *Client) Temperature(ctx context.Context, cep string, opts ...LookupOption) (Temperature, error) {
+	var l lookup
+	for _, opt := range opts {
+		opt(&l)
+	}
+
+	body, marshErr := json.Marshal(contract.CEPRequest{CEP: cep})
+	if marshErr != nil {
+		return Temperature{}, marshErr
+	}
+
+	endpoint := c.baseURL + contract.GetCepPath
+	if len(l.units) > 0 {
+		endpoint += "?" + url.Values{contract.QueryUnits: {strings.Join(l.units, ",")}}.Encode()
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.opts.retries; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt, lastErr); err != nil {
+				return Temperature{}, err
+			}
+		}
+
+		out, err := c.do(ctx, endpoint, body, l.language)
+		if err == nil {
+			return out, nil
+		}
+		lastErr = err
+
+		if !retryable(err) || ctx.Err() != nil {
+			return Temperature{}, err
+		}
+	}
+
+	return Temperature{}, lastErr
+}
+
+func (c *Client) do(ctx context.Context, endpoint string, body []byte, language string) (Temperature, error) {
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if reqErr != nil {
+		return Temperature{}, reqErr
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if language != "" {
+		req.Header.Set("Accept-Language", language)
+	}
+	if c.opts.apiKey != "" {
+		req.Header.Set(c.opts.apiKeyHeader, c.opts.apiKey)
+	}
+	c.propagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, doErr := c.opts.httpClient.Do(req)
+	if doErr != nil {
+		return Temperature{}, &transportError{doErr}
+	}
+	defer resp.Body.Close()
+
+	respBody, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return Temperature{}, &transportError{readErr}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Temperature{}, newStatusError(resp.StatusCode, resp.Header, respBody)
+	}
+
+	var out Temperature
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return Temperature{}, fmt.Errorf("decoding the response: %w", err)
+	}
+
+	return out, nil
+}
+
+func (c *Client) propagator() propagation.TextMapPropagator {
+	if c.opts.propagator != nil {
+		return c.opts.propagator
+	}
+
+	return otel.GetTextMapPropagator()
+}
+
+// wait sleeps before the given retry attempt.
+func (c *Client) wait(ctx context.Context, attempt int, lastErr error) error {
+	delay := c.opts.backoff << (attempt - 1)
+
+	var statusErr *StatusError
+	if errors.As(lastErr, &statusErr) && statusErr.RetryAfter > delay {
+		delay = statusErr.RetryAfter
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Result is the outcome of one lookup of a Batch.
+type Result struct {
+	CEP         string
+	Temperature Temperature
+	Err         error
+}
+
+// Batch looks up every CEP, running up to the configured concurrency at once,
+// and returns the results in the order of ceps.
+func (c *Client) Batch(ctx context.Context, ceps []string, opts ...LookupOption) []Result {
+	results := make([]Result, len(ceps))
+	sem := make(chan struct{}, c.opts.concurrency)
+
+	var wg sync.WaitGroup
+	for i, cep := range ceps {
+		wg.Add(1)
+		go func(i int, cep string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = Result{CEP: cep, Err: ctx.Err()}
+				return
+			}
+
+			out, err := c.Temperature(ctx, cep, opts...)
+			results[i] = Result{CEP: cep, Temperature: out, Err: err}
+		}(i, cep)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,326 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MatheusBenetti/opentelemetry/config"
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+	"github.com/MatheusBenetti/opentelemetry/internal/inputHandle/infra/web"
+	"github.com/MatheusBenetti/opentelemetry/internal/openapi"
+	"github.com/MatheusBenetti/opentelemetry/internal/temperature/entity"
+	"github.com/MatheusBenetti/opentelemetry/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// stubServiceB answers service A's calls to service B. Errors listed for a
+// CEP are returned one per call before it succeeds.
+type stubServiceB struct {
+	mu       sync.Mutex
+	errs     map[string][]error
+	calls    map[string]int
+	requests []contract.TemperatureRequest
+}
+
+func (s *stubServiceB) Temperature(_ context.Context, req contract.TemperatureRequest) (contract.TemperatureResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+	s.calls[req.CEP]++
+	if errs := s.errs[req.CEP]; len(errs) > 0 {
+		s.errs[req.CEP] = errs[1:]
+		return contract.TemperatureResponse{}, errs[0]
+	}
+	if req.CEP == "99999999" {
+		return contract.TemperatureResponse{}, entity.ErrCEPNotFound
+	}
+
+	celsius := 25.0
+	return contract.TemperatureResponse{Location: "Gramado", Condition: "Sunny", TempC: &celsius}, nil
+}
+
+func (s *stubServiceB) callsFor(cep string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[cep]
+}
+
+func startServiceA(t *testing.T, stub *stubServiceB, auth config.Auth) (*httptest.Server, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	spec, err := openapi.ServiceA()
+	require.NoError(t, err)
+
+	server := web.Server{
+		TemplateData: web.TemplateData{
+			RequestNameOtel: "service_a:all",
+			OTELTracer:      provider.Tracer("service_a"),
+		},
+		TemperatureClient: stub,
+		OpenAPI:           spec,
+		Middlewares:       []web.Middleware{web.NewAuthenticator(auth).Middleware},
+	}
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+
+	return ts, recorder
+}
+
+func newStub(errs map[string][]error) *stubServiceB {
+	if errs == nil {
+		errs = map[string][]error{}
+	}
+	return &stubServiceB{errs: errs, calls: map[string]int{}}
+}
+
+func TestTemperature(t *testing.T) {
+	stub := newStub(nil)
+	ts, _ := startServiceA(t, stub, config.Auth{})
+	c := client.New(ts.URL)
+
+	out, err := c.Temperature(context.Background(), "95670-084", client.Units("C", "F"), client.Language("pt-BR"))
+	require.NoError(t, err)
+	assert.Equal(t, "Gramado", out.City)
+	require.NotNil(t, out.TempC)
+	assert.Equal(t, 25.0, *out.TempC)
+
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, contract.TemperatureRequest{CEP: "95670084", Units: "C,F", Language: "pt"}, stub.requests[0])
+}
+
+func TestTypedErrors(t *testing.T) {
+	stub := newStub(nil)
+	ts, _ := startServiceA(t, stub, config.Auth{})
+	c := client.New(ts.URL, client.WithRetries(0, 0))
+
+	tests := []struct {
+		name   string
+		cep    string
+		opts   []client.LookupOption
+		errIs  error
+		status int
+	}{
+		{"invalid CEP", "123", nil, client.ErrCEPNotValid, http.StatusUnprocessableEntity},
+		{"unknown CEP", "99999999", nil, client.ErrCEPNotFound, http.StatusNotFound},
+		{"invalid unit", "95670084", []client.LookupOption{client.Units("X")}, client.ErrUnitNotValid, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.Temperature(context.Background(), tt.cep, tt.opts...)
+
+			var statusErr *client.StatusError
+			require.ErrorAs(t, err, &statusErr)
+			assert.Equal(t, tt.status, statusErr.StatusCode)
+			if tt.errIs != nil {
+				assert.ErrorIs(t, err, tt.errIs)
+			}
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	stub := newStub(map[string][]error{
+		"95670084": {entity.ErrWeatherUnavailable, entity.ErrWeatherUnavailable},
+		"01001000": {entity.ErrWeatherQuotaExceeded, entity.ErrWeatherQuotaExceeded, entity.ErrWeatherQuotaExceeded},
+		"20040020": {entity.ErrWeatherAPIKeyInvalid, entity.ErrWeatherAPIKeyInvalid},
+		"30130010": {fmt.Errorf("%w: status 400", entity.ErrCEPProviderBadRequest)},
+	})
+	ts, _ := startServiceA(t, stub, config.Auth{})
+	c := client.New(ts.URL, client.WithRetries(2, time.Millisecond))
+
+	out, err := c.Temperature(context.Background(), "95670084")
+	require.NoError(t, err)
+	assert.Equal(t, "Gramado", out.City)
+	assert.Equal(t, 3, stub.callsFor("95670084"))
+
+	_, err = c.Temperature(context.Background(), "01001000")
+	assert.ErrorIs(t, err, client.ErrUnavailable)
+	assert.ErrorIs(t, err, client.ErrWeatherQuotaExceeded)
+	assert.Equal(t, 3, stub.callsFor("01001000"))
+
+	_, err = c.Temperature(context.Background(), "99999999")
+	assert.ErrorIs(t, err, client.ErrCEPNotFound)
+	assert.Equal(t, 1, stub.callsFor("99999999"), "not found is not retried")
+
+	_, err = c.Temperature(context.Background(), "20040020")
+	assert.ErrorIs(t, err, client.ErrWeatherAPIKeyInvalid)
+	assert.NotErrorIs(t, err, client.ErrUnavailable)
+	assert.Equal(t, 1, stub.callsFor("20040020"), "a rejected API key is not retried")
+
+	_, err = c.Temperature(context.Background(), "30130010")
+	assert.ErrorIs(t, err, client.ErrCEPProviderBadRequest)
+	assert.Equal(t, 1, stub.callsFor("30130010"), "a rejected request is not retried")
+}
+
+func TestRetriesOnlyTransportAndStatusErrors(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			// Drop the connection without answering.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"city": `))
+		}
+	}))
+	defer ts.Close()
+
+	c := client.New(ts.URL, client.WithRetries(3, time.Millisecond))
+	_, err := c.Temperature(context.Background(), "95670084")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "decoding the response")
+	assert.Equal(t, int32(2), calls.Load(), "the dropped connection is retried, the malformed body is not")
+}
+
+func TestStatusErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		errIs    []error
+		errIsNot []error
+	}{
+		{
+			name:   "code matching the status",
+			status: http.StatusServiceUnavailable,
+			body:   `{"code":"cep_provider_unavailable","message":"zipcode provider unavailable: status 500"}`,
+			errIs:  []error{client.ErrCEPProviderUnavailable, client.ErrUnavailable},
+		},
+		{
+			name:     "code disagreeing with the status",
+			status:   http.StatusBadGateway,
+			body:     `{"code":"cep_not_found","message":"can not found zipcode"}`,
+			errIs:    []error{client.ErrUnavailable},
+			errIsNot: []error{client.ErrCEPNotFound},
+		},
+		{
+			name:     "proxy error page",
+			status:   http.StatusBadGateway,
+			body:     `<html>bad gateway</html>`,
+			errIs:    []error{client.ErrUnavailable},
+			errIsNot: []error{client.ErrWeatherAPIKeyInvalid},
+		},
+		{
+			name:     "message without a code",
+			status:   http.StatusNotFound,
+			body:     "can not found zipcode",
+			errIsNot: []error{client.ErrCEPNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			_, err := client.New(ts.URL, client.WithRetries(0, 0)).Temperature(context.Background(), "95670084")
+			for _, target := range tt.errIs {
+				assert.ErrorIs(t, err, target)
+			}
+			for _, target := range tt.errIsNot {
+				assert.NotErrorIs(t, err, target)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	stub := newStub(map[string][]error{"95670084": {entity.ErrWeatherUnavailable}})
+	ts, _ := startServiceA(t, stub, config.Auth{})
+	c := client.New(ts.URL, client.WithRetries(3, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.Temperature(ctx, "95670084")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, stub.callsFor("95670084"))
+}
+
+func TestAPIKey(t *testing.T) {
+	ts, _ := startServiceA(t, newStub(nil), config.Auth{
+		Enabled: true,
+		Header:  "X-API-Key",
+		Keys: []config.APIKey{{
+			ID:   "local-dev",
+			Hash: "ed5a18fb8f807f996d649e379d3f35f39c543a91bdbf88c492f2ebd10d4df86c",
+		}},
+	})
+
+	_, err := client.New(ts.URL).Temperature(context.Background(), "95670084")
+	assert.ErrorIs(t, err, client.ErrUnauthorized)
+
+	_, err = client.New(ts.URL, client.WithAPIKey("local-dev-key")).Temperature(context.Background(), "95670084")
+	assert.NoError(t, err)
+}
+
+func TestTracePropagation(t *testing.T) {
+	ts, recorder := startServiceA(t, newStub(nil), config.Auth{})
+
+	callerProvider := sdktrace.NewTracerProvider()
+	ctx, span := callerProvider.Tracer("caller").Start(context.Background(), "caller")
+	_, err := client.New(ts.URL).Temperature(ctx, "95670084")
+	span.End()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(recorder.Ended()) == 1 }, time.Second, 10*time.Millisecond)
+	serverSpan := recorder.Ended()[0]
+	assert.Equal(t, span.SpanContext().TraceID(), serverSpan.SpanContext().TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+}
+
+func TestBatch(t *testing.T) {
+	stub := newStub(nil)
+	ts, _ := startServiceA(t, stub, config.Auth{})
+	c := client.New(ts.URL, client.WithConcurrency(2), client.WithRetries(0, 0))
+
+	ceps := []string{"95670084", "123", "99999999", "95670-084"}
+	results := c.Batch(context.Background(), ceps, client.Units("K"))
+	require.Len(t, results, len(ceps))
+
+	for i, result := range results {
+		assert.Equal(t, ceps[i], result.CEP)
+	}
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "Gramado", results[0].Temperature.City)
+	assert.ErrorIs(t, results[1].Err, client.ErrCEPNotValid)
+	assert.ErrorIs(t, results[2].Err, client.ErrCEPNotFound)
+	assert.NoError(t, results[3].Err)
+
+	for _, req := range stub.requests {
+		assert.Equal(t, "K", req.Units)
+	}
+}
+
+func TestStatusErrorMessage(t *testing.T) {
+	stub := newStub(map[string][]error{"95670084": {errors.New("boom")}})
+	ts, _ := startServiceA(t, stub, config.Auth{})
+
+	_, err := client.New(ts.URL, client.WithRetries(0, 0)).Temperature(context.Background(), "95670084")
+	assert.EqualError(t, err, fmt.Sprintf("service A responded with status %d: boom", http.StatusInternalServerError))
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	contract "github.com/MatheusBenetti/opentelemetry/internal/contract/v1"
+)
+
+// Errors service A answers with. Match them with errors.Is.
+var (
+	ErrCEPNotValid             = errors.New("invalid CEP")
+	ErrCEPNotFound             = errors.New("CEP not found")
+	ErrUnitNotValid            = errors.New("invalid temperature unit")
+	ErrWeatherLocationNotFound = errors.New("no weather for the CEP's city")
+
+	// ErrCEPProviderBadRequest and ErrWeatherAPIKeyInvalid come with a 502
+	// but are misconfigurations of service B, so they aren't retried.
+	ErrCEPProviderBadRequest = errors.New("zipcode provider rejected service B's request")
+	ErrWeatherAPIKeyInvalid  = errors.New("weather provider API key of service B missing or rejected")
+
+	ErrCEPProviderRateLimited = errors.New("zipcode provider rate limit exceeded")
+	ErrCEPProviderUnavailable = errors.New("zipcode provider unavailable")
+	ErrWeatherQuotaExceeded   = errors.New("weather provider quota exceeded")
+	ErrWeatherUnavailable     = errors.New("weather provider unavailable")
+
+	ErrUnauthorized = errors.New("missing or invalid API key")
+	ErrRateLimited  = errors.New("rate limited by service A")
+	ErrUnavailable  = errors.New("service A or one of its upstreams is unavailable")
+)
+
+// codeErrors maps the codes of service A's error responses to the errors
+// above. Transient errors also unwrap to ErrUnavailable and are retried.
+var codeErrors = map[string]struct {
+	err       error
+	transient bool
+}{
+	contract.CodeCEPNotValid:             {ErrCEPNotValid, false},
+	contract.CodeCEPNotFound:             {ErrCEPNotFound, false},
+	contract.CodeUnitNotValid:            {ErrUnitNotValid, false},
+	contract.CodeWeatherLocationNotFound: {ErrWeatherLocationNotFound, false},
+	contract.CodeCEPProviderBadRequest:   {ErrCEPProviderBadRequest, false},
+	contract.CodeWeatherAPIKeyInvalid:    {ErrWeatherAPIKeyInvalid, false},
+	contract.CodeEmptyAPIKey:             {ErrWeatherAPIKeyInvalid, false},
+	contract.CodeCEPProviderRateLimited:  {ErrCEPProviderRateLimited, true},
+	contract.CodeCEPProviderUnavailable:  {ErrCEPProviderUnavailable, true},
+	contract.CodeWeatherQuotaExceeded:    {ErrWeatherQuotaExceeded, true},
+	contract.CodeWeatherUnavailable:      {ErrWeatherUnavailable, true},
+}
+
+// StatusError is returned for every non-200 answer. It unwraps to the errors
+// above that the status and error code identify, e.g. both ErrUnavailable and
+// ErrWeatherQuotaExceeded for a 503 caused by the weather quota.
+type StatusError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is the wait service A asked for when throttling.
+	RetryAfter time.Duration
+	errs       []error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("service A responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *StatusError) Unwrap() []error {
+	return e.errs
+}
+
+func newStatusError(status int, header http.Header, body []byte) *StatusError {
+	statusErr := &StatusError{
+		StatusCode: status,
+		Message:    strings.TrimSpace(string(body)),
+	}
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		statusErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	errResp, coded := contract.ParseErrorResponse(body)
+	if coded {
+		statusErr.Message = errResp.Message
+	}
+
+	// The code is only trusted when the status is the one service A answers
+	// it with, so a proxy's error page can't be mistaken for it.
+	known, found := codeErrors[errResp.Code]
+	if found && contract.StatusFor(contract.ErrorForCode(errResp.Code)) == status {
+		statusErr.errs = append(statusErr.errs, known.err)
+	} else {
+		found = false
+	}
+
+	switch status {
+	case http.StatusUnauthorized:
+		statusErr.errs = append(statusErr.errs, ErrUnauthorized)
+	case http.StatusTooManyRequests:
+		statusErr.errs = append(statusErr.errs, ErrRateLimited)
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !found || known.transient {
+			statusErr.errs = append(statusErr.errs, ErrUnavailable)
+		}
+	}
+
+	return statusErr
+}
+
+// retryable reports whether the request may succeed if sent again.
+func (e *StatusError) retryable() bool {
+	return errors.Is(e, ErrRateLimited) || errors.Is(e, ErrUnavailable)
+}
+
+// transportError is a failure to send the request or read the response,
+// which may not happen again.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// retryable reports whether err, returned by a single request, is worth
+// sending the request again for: transport failures and the statuses
+// StatusError.retryable accepts. A success whose body can't be decoded is not.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.retryable()
+	}
+
+	var transportErr *transportError
+	return errors.As(err, &transportErr)
+}
@@ -0,0 +1,48 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/MatheusBenetti/opentelemetry/pkg/client"
+)
+
+func Example() {
+	c := client.New("http://localhost:8080", client.WithAPIKey("local-dev-key"))
+
+	temp, err := c.Temperature(context.Background(), "95670-084", client.Units("C", "F"), client.Language("pt-BR"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s: %.1f°C, %s\n", temp.City, *temp.TempC, temp.Condition)
+}
+
+func ExampleClient_Temperature_errors() {
+	c := client.New("http://localhost:8080")
+
+	_, err := c.Temperature(context.Background(), "00000000")
+	switch {
+	case errors.Is(err, client.ErrCEPNotValid):
+		fmt.Println("CEP must have 8 digits")
+	case errors.Is(err, client.ErrCEPNotFound):
+		fmt.Println("CEP does not exist")
+	case errors.Is(err, client.ErrUnavailable):
+		fmt.Println("try again later")
+	case err != nil:
+		log.Fatal(err)
+	}
+}
+
+func ExampleClient_Batch() {
+	c := client.New("http://localhost:8080", client.WithConcurrency(8))
+
+	for _, result := range c.Batch(context.Background(), []string{"95670084", "01001000"}, client.Units("C")) {
+		if result.Err != nil {
+			fmt.Printf("%s: %v\n", result.CEP, result.Err)
+			continue
+		}
+		fmt.Printf("%s: %.1f°C\n", result.CEP, *result.Temperature.TempC)
+	}
+}